@@ -0,0 +1,96 @@
+package main
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+// dryRunFlag implements flag.Value for "-dry-run" with an optional FORMAT, e.g. "-dry-run" and "-dry-run=json".
+type dryRunFlag struct {
+	format string
+}
+
+// String implements flag.Value.
+func (f *dryRunFlag) String() string {
+	return ""
+}
+
+// IsBoolFlag makes the value optional as the same as boolean flags.
+func (f *dryRunFlag) IsBoolFlag() bool {
+	return true
+}
+
+// Set implements flag.Value.
+func (f *dryRunFlag) Set(value string) error {
+	if b, err := strconv.ParseBool(value); err == nil {
+		f.format = ""
+		if b {
+			f.format = "table"
+		}
+		return nil
+	}
+
+	switch value {
+	case "table", "json":
+		f.format = value
+		return nil
+	}
+	return fmt.Errorf("unexpected format %#v", value)
+}
+
+// runPlan prints the plan of the split of mode by value in the format of dryRun,
+// and exits with an error if the split would fail.
+func runPlan(g *gosplit.GoSplit, mode gosplit.SplitMode, value int64) {
+	plan, err := g.Plan(mode, value)
+	if err != nil {
+		exitIfInterrupted(err)
+		fmt.Fprintln(os.Stderr, err)
+		log.Fatalf("%+v", err)
+	}
+
+	if dryRun.format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(plan)
+	} else {
+		err = writePlanTable(os.Stdout, plan)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		log.Fatalf("%+v", err)
+	}
+
+	if err := plan.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		log.Fatalf("%+v", err)
+	}
+}
+
+// writePlanTable writes the output files of plan as a table to w.
+func writePlanTable(w io.Writer, plan *gosplit.Plan) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tOFFSET\tLENGTH\tLINES")
+	for _, chunk := range plan.Chunks {
+		offset, lines := "-", "-"
+		if chunk.Offset >= 0 {
+			offset = strconv.FormatInt(chunk.Offset, 10)
+		}
+		if chunk.FirstLine >= 0 {
+			lines = fmt.Sprintf("%d-%d", chunk.FirstLine, chunk.LastLine)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", chunk.Name, offset, chunk.Length, lines)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%d output files\n", len(plan.Chunks))
+	return err
+}
@@ -0,0 +1,123 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path"
+	"strconv"
+)
+
+// atomicFile is an output file written to a temporary name, which is renamed to the final path on Close.
+type atomicFile struct {
+	f        *os.File
+	tmpPath  string
+	filePath string
+}
+
+// createAtomicFile creates atomicFile of filePath, whose temporary name is hidden in the same directory.
+func createAtomicFile(filePath string) (*atomicFile, error) {
+	dir, base := path.Split(filePath)
+	for i := 0; ; i++ {
+		tmpPath := dir + "." + base + ".tmp-" + strconv.FormatUint(uint64(rand.Uint32()), 10)
+		f, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666)
+		if errors.Is(err, fs.ErrExist) && i < 100 {
+			continue
+		}
+		if err != nil {
+			return nil, wrapper.Errorf("failed to create: %w", err)
+		}
+		return &atomicFile{f: f, tmpPath: tmpPath, filePath: filePath}, nil
+	}
+}
+
+// Write implements io.Writer.
+func (a *atomicFile) Write(p []byte) (int, error) {
+	return a.f.Write(p)
+}
+
+// Close implements io.Closer, renaming the temporary file to the final path.
+// The temporary file is removed if it fails.
+func (a *atomicFile) Close() error {
+	if err := a.f.Close(); err != nil {
+		os.Remove(a.tmpPath)
+		return wrapper.Errorf("failed to close: %w", err)
+	}
+	if err := os.Rename(a.tmpPath, a.filePath); err != nil {
+		os.Remove(a.tmpPath)
+		return wrapper.Errorf("failed to rename: %w", err)
+	}
+	return nil
+}
+
+// Abort implements aborter, removing the temporary file without renaming it.
+func (a *atomicFile) Abort() error {
+	a.f.Close()
+	if err := os.Remove(a.tmpPath); err != nil {
+		return wrapper.Errorf("failed to remove: %w", err)
+	}
+	return nil
+}
+
+// aborter is implemented by the writers of output files which can be discarded when they are incomplete.
+type aborter interface {
+	// Abort discards the output file instead of Close.
+	Abort() error
+}
+
+// abortOutFile discards the incomplete output file w after an error, or closes it if w cannot be discarded.
+func abortOutFile(w io.WriteCloser) error {
+	if a, ok := w.(aborter); ok {
+		return a.Abort()
+	}
+	return w.Close()
+}
+
+// finishOutput ends the output files of a split, which has failed if gerr is not nil.
+//
+// The output files created in the directory by the failed split are removed unless keepPartial or resuming,
+// so that they are not mixed with the output files of a retry. It returns gerr with the errors of removing,
+// or ErrInterrupted or the error of the context reporting the output files if the split has been interrupted.
+func (g *GoSplit) finishOutput(gerr g.Error) g.Error {
+	created := g.created
+	nCompleted := g.nCompleted.Swap(0)
+	nDiscarded := g.nDiscarded.Swap(0)
+	g.created = nil
+	bResuming := g.journal != nil
+	g.finishProgress()
+	if err := g.finishJournal(gerr == nil); err != nil && gerr == nil {
+		gerr = err
+	}
+
+	// the output files in archives and the outputs of the filter command cannot be removed
+	ds, ok := g.sink.(*DirSink)
+	bRemove := gerr != nil && !g.bKeepPartial && !bResuming && ok && g.filterCommand == ""
+	if isInterrupted(gerr) {
+		cause := ErrInterrupted
+		if g.ctx != nil && g.ctx.Err() != nil && !errors.Is(gerr, ErrInterrupted) {
+			cause = g.ctx.Err()
+		}
+		gerr = interruptedError(cause, int(nCompleted), len(created)-int(nDiscarded), bRemove)
+	}
+	if !bRemove {
+		return gerr
+	}
+
+	errs := []error{gerr}
+	for _, name := range created {
+		filePath := ds.Path(name)
+		fmt.Fprintf(g.wVerbose, "removing file %#v\n", filePath)
+		if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 1 {
+		return wrapper.Errorf("%w", errors.Join(errs...))
+	}
+	return gerr
+}
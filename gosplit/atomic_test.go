@@ -0,0 +1,121 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestOptions_KeepPartial(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		bKeepPartial bool
+		split        func(g *gosplit.GoSplit) error
+		wantFiles    int
+	}{
+		"ByLines rollback":      {false, func(g *gosplit.GoSplit) error { return g.ByLines(1) }, 0},
+		"ByLines keep":          {true, func(g *gosplit.GoSplit) error { return g.ByLines(1) }, 10},
+		"ByBytes rollback":      {false, func(g *gosplit.GoSplit) error { return g.ByBytes(100) }, 0},
+		"ByLineBytes rollback":  {false, func(g *gosplit.GoSplit) error { return g.ByLineBytes(100) }, 0},
+		"ByNumber rollback":     {false, func(g *gosplit.GoSplit) error { return g.ByNumber(11) }, 0},
+		"ByRoundRobin rollback": {false, func(g *gosplit.GoSplit) error { return g.ByRoundRobin(11) }, 0},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			outDir := t.TempDir()
+			opts := gosplit.Options{
+				OutDir:      outDir,
+				Suffix:      gosplit.Suffix{Type: gosplit.SuffixNumeric, Length: 1},
+				KeepPartial: tt.bKeepPartial,
+			}
+			g := helperNew(t, "testdata/example.txt", "x", opts)
+			err := tt.split(g)
+			if !errors.Is(err, gosplit.ErrSuffixExhausted) {
+				t.Fatalf("split = %#v, want %#v", err, gosplit.ErrSuffixExhausted)
+			}
+
+			entries, err := os.ReadDir(outDir)
+			if err != nil {
+				t.Fatal("failed to read dir:", err)
+			}
+			if len(entries) != tt.wantFiles {
+				t.Errorf("%d files left in the output directory, want %d", len(entries), tt.wantFiles)
+			}
+		})
+	}
+}
+
+func TestDirSink_Atomic(t *testing.T) {
+	t.Parallel()
+
+	outDir := t.TempDir()
+	sink := gosplit.NewDirSink(outDir)
+	w, err := sink.Create("xaa")
+	if err != nil {
+		t.Fatal("Create() failed:", err)
+	}
+	if _, err := w.Write([]byte("a\n")); err != nil {
+		t.Fatal("Write() failed:", err)
+	}
+
+	// the output file appears only after closing
+	if _, err := os.Stat(outDir + "/xaa"); err == nil {
+		t.Errorf("output file exists before closing")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal("Close() failed:", err)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal("failed to read dir:", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "xaa" {
+		t.Errorf("files in the output directory = %#v, want only %#v", entries, "xaa")
+	}
+	b, err := os.ReadFile(outDir + "/xaa")
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+	if string(b) != "a\n" {
+		t.Errorf("content = %#v, want %#v", string(b), "a\n")
+	}
+}
+
+func TestDirSink_Abort(t *testing.T) {
+	t.Parallel()
+
+	outDir := t.TempDir()
+	sink := gosplit.NewDirSink(outDir)
+	w, err := sink.Create("xaa")
+	if err != nil {
+		t.Fatal("Create() failed:", err)
+	}
+	if _, err := w.Write([]byte("a\n")); err != nil {
+		t.Fatal("Write() failed:", err)
+	}
+
+	a, ok := w.(interface{ Abort() error })
+	if !ok {
+		t.Fatal("the writer of DirSink has no Abort()")
+	}
+	if err := a.Abort(); err != nil {
+		t.Fatal("Abort() failed:", err)
+	}
+
+	// neither the output file nor the temporary file is left
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal("failed to read dir:", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d files left in the output directory, want 0", len(entries))
+	}
+}
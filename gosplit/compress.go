@@ -0,0 +1,346 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"os"
+)
+
+// Compression represents the compression format of output files.
+type Compression int
+
+// Compression formats.
+const (
+	// CompressNone writes output files as they are, which is the default.
+	CompressNone Compression = iota
+	// CompressGzip compresses output files with gzip, appending ".gz".
+	CompressGzip
+	// CompressZlib compresses output files with zlib, appending ".zz".
+	CompressZlib
+	// CompressFlate compresses output files with raw DEFLATE, appending ".deflate".
+	CompressFlate
+)
+
+// ParseCompression converts strCompression to Compression, e.g. "gzip" -> CompressGzip.
+func ParseCompression(strCompression string) (Compression, error) {
+	switch strCompression {
+	case "", "none":
+		return CompressNone, nil
+	case "gzip":
+		return CompressGzip, nil
+	case "zlib":
+		return CompressZlib, nil
+	case "flate":
+		return CompressFlate, nil
+	}
+	return CompressNone, wrapper.Errorf("%w: %#v", ErrInvalidCompression, strCompression)
+}
+
+// Extension returns the extension appended to output file names.
+func (c Compression) Extension() string {
+	switch c {
+	case CompressGzip:
+		return ".gz"
+	case CompressZlib:
+		return ".zz"
+	case CompressFlate:
+		return ".deflate"
+	default:
+		return ""
+	}
+}
+
+// validate checks that c and level are valid. level 0 means the default level.
+func (c Compression) validate(level int) g.Error {
+	switch c {
+	case CompressNone, CompressGzip, CompressZlib, CompressFlate:
+	default:
+		return wrapper.Errorf("%w: unknown compression %#v", ErrInvalidCompression, c)
+	}
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return wrapper.Errorf("%w: level %#v", ErrInvalidCompression, level)
+	}
+	return nil
+}
+
+// flushWriter is a compressor which can flush the pending output.
+type flushWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// newCompressor returns a compressor writing to w with level, or nil with CompressNone.
+func (c Compression) newCompressor(w io.Writer, level int) (flushWriter, error) {
+	if level == 0 {
+		level = flate.DefaultCompression
+	}
+
+	switch c {
+	case CompressGzip:
+		return gzip.NewWriterLevel(w, level)
+	case CompressZlib:
+		return zlib.NewWriterLevel(w, level)
+	case CompressFlate:
+		return flate.NewWriter(w, level)
+	default:
+		return nil, nil
+	}
+}
+
+// newDecompressor returns a decompressor reading from r, or r itself with CompressNone.
+func (c Compression) newDecompressor(r io.Reader) (io.ReadCloser, error) {
+	switch c {
+	case CompressGzip:
+		return gzip.NewReader(r)
+	case CompressZlib:
+		return zlib.NewReader(r)
+	case CompressFlate:
+		return flate.NewReader(r), nil
+	default:
+		return io.NopCloser(r), nil
+	}
+}
+
+// sizer is implemented by the writers of output files which can report the size counted for the byte modes.
+type sizer interface {
+	// outSize returns the number of bytes written to the output file so far, flushing the pending output.
+	outSize() (int64, error)
+}
+
+// compressWriter compresses an output file, counting the compressed bytes.
+type compressWriter struct {
+	zw      flushWriter
+	w       io.WriteCloser
+	written int64
+}
+
+// newCompressWriter returns a new compressWriter writing to w.
+func (g *GoSplit) newCompressWriter(w io.WriteCloser) (*compressWriter, g.Error) {
+	cw := &compressWriter{w: w}
+	zw, err := g.compression.newCompressor(writerFunc(func(p []byte) (int, error) {
+		n, err := w.Write(p)
+		cw.written += int64(n)
+		return n, err
+	}), g.compressLevel)
+	if err != nil {
+		return nil, wrapper.Errorf("%w: %w", ErrInvalidCompression, err)
+	}
+	cw.zw = zw
+	return cw, nil
+}
+
+// writerFunc adapts a function to io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+// Write implements io.Writer.
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+// Write implements io.Writer.
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	return cw.zw.Write(p)
+}
+
+// Close implements io.Closer, closing the output file after the compressor.
+// The output file is discarded if the compressor fails to write its rest.
+func (cw *compressWriter) Close() error {
+	if err := cw.zw.Close(); err != nil {
+		abortOutFile(cw.w)
+		return err
+	}
+	return cw.w.Close()
+}
+
+// Abort implements aborter, discarding the output file without finishing the compressor.
+func (cw *compressWriter) Abort() error {
+	return abortOutFile(cw.w)
+}
+
+// outSize implements sizer, returning the compressed size.
+func (cw *compressWriter) outSize() (int64, error) {
+	if err := cw.zw.Flush(); err != nil {
+		return 0, err
+	}
+	return cw.written, nil
+}
+
+// outSize implements sizer if the underlying writer does.
+func (m *manifestWriter) outSize() (int64, error) {
+	if s, ok := m.WriteCloser.(sizer); ok {
+		return s.outSize()
+	}
+	return m.counter.size, nil
+}
+
+// openOutFile opens the output file of filePath, decompressing it if needed.
+//
+// The errors of decompression wrap ErrModifiedChunk, or ErrTruncatedChunk on the unexpected end.
+func (g *GoSplit) openOutFile(filePath string) (io.ReadCloser, g.Error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, wrapper.Errorf("failed to open: %w", err)
+	}
+
+	r, errDecompress := g.compression.newDecompressor(f)
+	if errDecompress != nil {
+		f.Close()
+		return nil, decompressError(filePath, errDecompress)
+	}
+	return &outFileReader{r: r, f: f, filePath: filePath}, nil
+}
+
+// outFileReader reads the output file through the decompressor.
+type outFileReader struct {
+	r        io.ReadCloser
+	f        io.Closer
+	filePath string
+}
+
+// Read implements io.Reader, wrapping the errors of decompression.
+func (o *outFileReader) Read(p []byte) (int, error) {
+	n, err := o.r.Read(p)
+	if err != nil && err != io.EOF {
+		return n, decompressError(o.filePath, err)
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (o *outFileReader) Close() error {
+	o.r.Close()
+	return o.f.Close()
+}
+
+// decompressError returns the error of decompressing the output file of filePath.
+func decompressError(filePath string, err error) g.Error {
+	// the errors of reading the file itself are passed through by the decompressors
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return wrapper.Errorf("failed to read: %w", err)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return wrapper.Errorf("%w: %#v: %w", ErrTruncatedChunk, filePath, err)
+	}
+	return wrapper.Errorf("%w: %#v: %w", ErrModifiedChunk, filePath, err)
+}
+
+// sampleSize is the size of the head of the input compressed to estimate the compression ratio.
+const sampleSize = 1024 * 1024
+
+// estimateCompressedSize returns the estimated size of the input after compression.
+//
+// The head of the input is compressed and then the input is rewound.
+// The input which cannot be rewound, such as the decompressed one, is estimated not to shrink.
+func (g *GoSplit) estimateCompressedSize(in *input) (int64, g.Error) {
+	if in.size == 0 || in.rs == nil {
+		return in.size, nil
+	}
+
+	var compressed int64
+	zw, err := g.compression.newCompressor(writerFunc(func(p []byte) (int, error) {
+		compressed += int64(len(p))
+		return len(p), nil
+	}), g.compressLevel)
+	if err != nil {
+		return 0, wrapper.Errorf("%w: %w", ErrInvalidCompression, err)
+	}
+
+	sampled, err := io.Copy(zw, io.LimitReader(in.rs, sampleSize))
+	if err != nil {
+		return 0, wrapper.Errorf("failed to read: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return 0, wrapper.Errorf("failed to compress: %w", err)
+	}
+	if _, err := in.rs.Seek(0, io.SeekStart); err != nil {
+		return 0, wrapper.Errorf("failed to seek: %w", err)
+	}
+	if sampled == 0 {
+		return 0, nil
+	}
+
+	// in.size * compressed / sampled without overflow, rounded up
+	return in.size/sampled*compressed + (in.size%sampled*compressed+sampled-1)/sampled, nil
+}
+
+// minCompressRoom is the least room of an output file to continue copying when counting the compressed bytes,
+// so that the output file is not flushed too many times at the end.
+const minCompressRoom = 64
+
+// compressOverhead is the bytes reserved for the header, the trailer and the final block of the compression format.
+const compressOverhead = 32
+
+// The bounds of the DEFLATE blocks of compress/flate, where a block is never larger than a stored block.
+const (
+	// storedBlockSize is the least input bytes of a block unless it is flushed, i.e. 16384 literals.
+	storedBlockSize = 16384
+	// storedBlockOverhead is the header of a stored block, including the bits of the previous block.
+	storedBlockOverhead = 5
+	// flushOverhead is the empty stored block written by a flush.
+	flushOverhead = 5
+)
+
+// maxCompressedSize returns the upper bound of the compressed size of n input bytes followed by a flush.
+func maxCompressedSize(n int64) int64 {
+	return n + (n/storedBlockSize+1)*storedBlockOverhead + flushOverhead
+}
+
+// maxCompressRoom returns the most input bytes whose compressed size followed by a flush fits in room.
+func maxCompressRoom(room int64) int64 {
+	r := room - storedBlockOverhead - flushOverhead
+	if r <= 0 {
+		return 0
+	}
+
+	n := r/(storedBlockSize+storedBlockOverhead)*storedBlockSize + min(r%(storedBlockSize+storedBlockOverhead), storedBlockSize)
+	for n > 0 && maxCompressedSize(n) > room {
+		n--
+	}
+	return n
+}
+
+// copyCompressed copies from r to the output file w until its compressed size reaches nBytes.
+//
+// The input bytes whose compressed size cannot exceed the rest of nBytes are copied at once,
+// and then the compressor is flushed to measure the size. If nBytes is too small for the overhead,
+// nBytes or minCompressRoom input bytes are copied. It returns io.EOF when r is exhausted.
+func copyCompressed(w io.Writer, r io.Reader, nBytes int64) error {
+	s, ok := w.(sizer)
+	if !ok {
+		_, err := io.CopyN(w, r, nBytes)
+		return err
+	}
+
+	room := max(maxCompressRoom(nBytes-compressOverhead), min(nBytes, minCompressRoom))
+	for {
+		if _, err := io.CopyN(w, r, room); err != nil {
+			return err
+		}
+		size, err := s.outSize()
+		if err != nil {
+			return err
+		}
+
+		room = maxCompressRoom(nBytes - size - compressOverhead)
+		if room < minCompressRoom {
+			return nil
+		}
+	}
+}
+
+// outFileSize returns the compressed size of the output file w including compressOverhead,
+// flushing the pending output.
+func outFileSize(w io.Writer) (int64, error) {
+	s, ok := w.(sizer)
+	if !ok {
+		return 0, wrapper.Errorf("%w: the size of output file is not known", ErrInvalidCompression)
+	}
+	size, err := s.outSize()
+	return size + compressOverhead, err
+}
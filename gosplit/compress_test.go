@@ -0,0 +1,245 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"slices"
+	"testing"
+)
+
+func TestParseCompression(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		in        string
+		want      gosplit.Compression
+		expectErr bool
+	}{
+		"empty":   {"", gosplit.CompressNone, false},
+		"none":    {"none", gosplit.CompressNone, false},
+		"gzip":    {"gzip", gosplit.CompressGzip, false},
+		"zlib":    {"zlib", gosplit.CompressZlib, false},
+		"flate":   {"flate", gosplit.CompressFlate, false},
+		"unknown": {"bzip2", gosplit.CompressNone, true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := gosplit.ParseCompression(tt.in)
+			if tt.expectErr && !errors.Is(err, gosplit.ErrInvalidCompression) {
+				t.Fatalf("ParseCompression(%#v) = %#v, want %#v", tt.in, err, gosplit.ErrInvalidCompression)
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			if tt.want != got {
+				t.Errorf("ParseCompression(%#v) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions_Compress(t *testing.T) {
+	t.Parallel()
+
+	sink := gosplit.NewMemorySink()
+	g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{Sink: sink, Compress: gosplit.CompressGzip})
+	if err := g.ByLines(20); err != nil {
+		t.Fatal("ByLines() failed:", err)
+	}
+
+	want := []int{20, 20, 2}
+	names := sink.Names()
+	if len(names) != len(want) {
+		t.Fatalf("Names() = %#v, want %d files", names, len(want))
+	}
+	for i, name := range names {
+		if path.Ext(name) != ".gz" {
+			t.Errorf("Names()[%d] = %#v, want the extension .gz", i, name)
+		}
+
+		content, _ := sink.Bytes(name)
+		zr, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			t.Fatal("failed to decompress:", err)
+		}
+		b, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal("failed to decompress:", err)
+		}
+		if got := bytes.Count(b, []byte{'\n'}); got != want[i] {
+			t.Errorf("lines of %#v = %d, want %d", name, got, want[i])
+		}
+	}
+}
+
+func TestOptions_CountCompressed(t *testing.T) {
+	t.Parallel()
+
+	// random bytes are hardly compressible, then the compressed size is close to the limit
+	input := make([]byte, 1000000)
+	rand.New(rand.NewSource(1)).Read(input)
+
+	cases := map[string]struct {
+		compression gosplit.Compression
+		level       int
+		nBytes      int64
+		bLineBytes  bool
+	}{
+		"ByBytes gzip":              {gosplit.CompressGzip, 0, 10000, false},
+		"ByBytes flate":             {gosplit.CompressFlate, 0, 10000, false},
+		"ByLineBytes zlib":          {gosplit.CompressZlib, 0, 10000, true},
+		"ByLineBytes flate":         {gosplit.CompressFlate, 0, 10000, true},
+		"ByBytes gzip large":        {gosplit.CompressGzip, 0, 100 * 1024, false},
+		"ByBytes gzip best speed":   {gosplit.CompressGzip, 1, 100 * 1024, false},
+		"ByBytes zlib huffman only": {gosplit.CompressZlib, -2, 100 * 1024, false},
+		"ByLineBytes gzip large":    {gosplit.CompressGzip, 0, 100 * 1024, true},
+		"ByLineBytes flate best":    {gosplit.CompressFlate, 9, 100 * 1024, true},
+		"ByLineBytes gzip huffman":  {gosplit.CompressGzip, -2, 100 * 1024, true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			outDir := t.TempDir()
+			opts := gosplit.Options{OutDir: outDir, Compress: tt.compression, CompressLevel: tt.level, CountCompressed: true}
+			g, gerr := gosplit.NewReader(bytes.NewReader(input), "x", opts)
+			if gerr != nil {
+				t.Fatal("NewReader() failed:", gerr)
+			}
+			split := g.ByBytes
+			if tt.bLineBytes {
+				split = g.ByLineBytes
+			}
+			if err := split(tt.nBytes); err != nil {
+				t.Fatal("split failed:", err)
+			}
+			nBytes := tt.nBytes
+
+			entries, err := os.ReadDir(outDir)
+			if err != nil {
+				t.Fatal("failed to read directory:", err)
+			}
+			// the output files are filled close to nBytes
+			if want := int64(len(input)) / nBytes * 2; int64(len(entries)) >= want {
+				t.Errorf("%d output files, want less than %d", len(entries), want)
+			}
+			for _, entry := range entries {
+				info, err := entry.Info()
+				if err != nil {
+					t.Fatal("failed to stat:", err)
+				}
+				if info.Size() > nBytes {
+					t.Errorf("size of %#v = %d, want at most %d", entry.Name(), info.Size(), nBytes)
+				}
+			}
+
+			var b bytes.Buffer
+			if err := gosplit.Join(&b, "x", gosplit.Options{OutDir: outDir, Compress: tt.compression}); err != nil {
+				t.Fatal("Join() failed:", err)
+			}
+			if !bytes.Equal(b.Bytes(), input) {
+				t.Errorf("Join() wrote %d bytes, want the same %d bytes as the input", b.Len(), len(input))
+			}
+		})
+	}
+}
+
+func TestVerify_Compress(t *testing.T) {
+	t.Parallel()
+
+	outDir := t.TempDir()
+	opts := gosplit.Options{OutDir: outDir, Compress: gosplit.CompressZlib}
+	var b bytes.Buffer
+	opts.Manifest = &b
+	g := helperNew(t, "testdata/example.txt", "x", opts)
+	if err := g.ByBytes(500); err != nil {
+		t.Fatal("ByBytes() failed:", err)
+	}
+	manifest, err := gosplit.ReadManifest(&b)
+	if err != nil {
+		t.Fatal("ReadManifest() failed:", err)
+	}
+
+	opts.Manifest = nil
+	if err := gosplit.Verify(manifest, "x", opts); err != nil {
+		t.Fatal("Verify() failed:", err)
+	}
+	if err := gosplit.VerifySource("testdata/example.txt", "x", opts); err != nil {
+		t.Fatal("VerifySource() failed:", err)
+	}
+
+	if err := os.Truncate(path.Join(outDir, "xab.zz"), 100); err != nil {
+		t.Fatal("failed to truncate:", err)
+	}
+	if err := gosplit.Verify(manifest, "x", opts); !errors.Is(err, gosplit.ErrTruncatedChunk) {
+		t.Errorf("Verify() = %v, want %v", err, gosplit.ErrTruncatedChunk)
+	}
+	if err := gosplit.VerifySource("testdata/example.txt", "x", opts); !errors.Is(err, gosplit.ErrTruncatedChunk) {
+		t.Errorf("VerifySource() = %v, want %v", err, gosplit.ErrTruncatedChunk)
+	}
+}
+
+// shortSink creates the output files which fail writing after limit bytes, recording how they end.
+type shortSink struct {
+	limit   int
+	closed  []string
+	aborted []string
+}
+
+func (s *shortSink) Create(name string) (io.WriteCloser, error) {
+	return &shortOutFile{s: s, name: name}, nil
+}
+
+// shortOutFile is the output file of shortSink.
+type shortOutFile struct {
+	s       *shortSink
+	name    string
+	written int
+}
+
+func (w *shortOutFile) Write(p []byte) (int, error) {
+	if w.written+len(p) > w.s.limit {
+		return 0, errors.New("no space left")
+	}
+	w.written += len(p)
+	return len(p), nil
+}
+
+func (w *shortOutFile) Close() error {
+	w.s.closed = append(w.s.closed, w.name)
+	return nil
+}
+
+func (w *shortOutFile) Abort() error {
+	w.s.aborted = append(w.s.aborted, w.name)
+	return nil
+}
+
+func TestOptions_Compress_CloseFailed(t *testing.T) {
+	t.Parallel()
+
+	// the gzip header is written first, and the compressed data fails to be written on Close
+	sink := &shortSink{limit: 10}
+	g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{Sink: sink, Compress: gosplit.CompressGzip})
+	if err := g.ByLines(10); err == nil {
+		t.Fatal("ByLines() should fail")
+	}
+
+	if len(sink.closed) != 0 {
+		t.Errorf("closed = %#v, want none", sink.closed)
+	}
+	if want := []string{"xaa.gz"}; !slices.Equal(sink.aborted, want) {
+		t.Errorf("aborted = %#v, want %#v", sink.aborted, want)
+	}
+}
@@ -0,0 +1,35 @@
+package gosplit
+
+import (
+	"context"
+)
+
+// ByLinesContext is the same as ByLines, but it stops when ctx is done.
+//
+// The context is checked before each output file and each write to it. When ctx is done, the split fails with
+// the error wrapping context.Canceled or context.DeadlineExceeded, and the output files are cleaned up as the
+// same as Interrupt.
+func (g *GoSplit) ByLinesContext(ctx context.Context, nLines int) error {
+	defer g.setContext(ctx)()
+	return g.ByLines(nLines)
+}
+
+// ByBytesContext is the same as ByBytes, but it stops when ctx is done as ByLinesContext.
+func (g *GoSplit) ByBytesContext(ctx context.Context, nBytes int64) error {
+	defer g.setContext(ctx)()
+	return g.ByBytes(nBytes)
+}
+
+// ByNumberContext is the same as ByNumber, but it stops when ctx is done as ByLinesContext.
+func (g *GoSplit) ByNumberContext(ctx context.Context, nNumber int) error {
+	defer g.setContext(ctx)()
+	return g.ByNumber(nNumber)
+}
+
+// setContext sets ctx as the context of the current split, returning the function to unset it.
+func (g *GoSplit) setContext(ctx context.Context) func() {
+	g.ctx = ctx
+	return func() {
+		g.ctx = nil
+	}
+}
@@ -0,0 +1,97 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cancelingWriter cancels the context when the verbose output contains trigger.
+type cancelingWriter struct {
+	cancel  context.CancelFunc
+	trigger string
+}
+
+func (w *cancelingWriter) Write(p []byte) (int, error) {
+	if strings.Contains(string(p), w.trigger) {
+		w.cancel()
+	}
+	return len(p), nil
+}
+
+func TestGoSplit_ByLinesContext(t *testing.T) {
+	t.Parallel()
+
+	outDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	verbose := &cancelingWriter{cancel: cancel, trigger: "xac"}
+	g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{OutDir: outDir, Verbose: verbose})
+
+	gerr := g.ByLinesContext(ctx, 10)
+	if !errors.Is(gerr, context.Canceled) {
+		t.Fatalf("ByLinesContext() = %#v, want %#v", gerr, context.Canceled)
+	}
+	if want := "context canceled: 2 output files completed, all removed"; !strings.HasSuffix(gerr.Error(), want) {
+		t.Errorf("ByLinesContext() = %#v, want %#v", gerr.Error(), want)
+	}
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal("failed to read dir:", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d files left in the output directory, want 0", len(entries))
+	}
+
+	// the context does not affect the later splits
+	if gerr := g.ByLines(10); gerr != nil {
+		t.Fatalf("ByLines() = %#v, want nil", gerr)
+	}
+	if got := helperCountLines(t, outDir, "xaa"); got != 10 {
+		t.Errorf("xaa has %d lines, want 10", got)
+	}
+}
+
+func TestGoSplit_Context_Done(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		jobs  int
+		split func(g *gosplit.GoSplit, ctx context.Context) error
+	}{
+		"ByLinesContext":       {0, func(g *gosplit.GoSplit, ctx context.Context) error { return g.ByLinesContext(ctx, 10) }},
+		"ByBytesContext":       {0, func(g *gosplit.GoSplit, ctx context.Context) error { return g.ByBytesContext(ctx, 100) }},
+		"ByBytesContext jobs":  {4, func(g *gosplit.GoSplit, ctx context.Context) error { return g.ByBytesContext(ctx, 100) }},
+		"ByNumberContext":      {0, func(g *gosplit.GoSplit, ctx context.Context) error { return g.ByNumberContext(ctx, 4) }},
+		"ByNumberContext jobs": {4, func(g *gosplit.GoSplit, ctx context.Context) error { return g.ByNumberContext(ctx, 4) }},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			outDir := t.TempDir()
+			g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{OutDir: outDir, Jobs: tt.jobs})
+			ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+			defer cancel()
+
+			err := tt.split(g, ctx)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("split = %#v, want %#v", err, context.DeadlineExceeded)
+			}
+			entries, err := os.ReadDir(outDir)
+			if err != nil {
+				t.Fatal("failed to read dir:", err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("%d files left in the output directory, want 0", len(entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// inputFormat is the compression format of the input detected by its magic bytes.
+type inputFormat struct {
+	name      string
+	newReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+// inputSampleSize is the size of the head of the input to detect its compression format.
+const inputSampleSize = 512
+
+// inputFormats are the compression formats of the input to decompress.
+//
+// The magic bytes of zlib are only 2 bytes with a checksum, which match the plain text by chance, e.g. "HK".
+// So the match is confirmed by decompressing the head of the input.
+var inputFormats = []struct {
+	match  func(sample []byte) bool
+	format inputFormat
+}{
+	{
+		func(header []byte) bool { return bytes.HasPrefix(header, []byte{0x1f, 0x8b}) },
+		inputFormat{"gzip", func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }},
+	},
+	{
+		func(header []byte) bool {
+			return len(header) >= 4 && bytes.HasPrefix(header, []byte("BZh")) && header[3] >= '1' && header[3] <= '9'
+		},
+		inputFormat{"bzip2", func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(bzip2.NewReader(r)), nil }},
+	},
+	{
+		// DEFLATE with the window size up to 32K, no preset dictionary and the valid check bits
+		func(sample []byte) bool {
+			if len(sample) < 2 || sample[0]&0x0f != 8 || sample[0]>>4 > 7 || sample[1]&0x20 != 0 ||
+				(uint16(sample[0])<<8|uint16(sample[1]))%31 != 0 {
+				return false
+			}
+			zr, err := zlib.NewReader(bytes.NewReader(sample))
+			return canDecompress(zr, err, len(sample) < inputSampleSize)
+		},
+		inputFormat{"zlib", func(r io.Reader) (io.ReadCloser, error) { return zlib.NewReader(r) }},
+	},
+}
+
+// canDecompress returns true if the sample of the input is decompressed by zr without errors,
+// where the sample may end in the middle of the compressed stream unless bWhole.
+func canDecompress(zr io.ReadCloser, err error, bWhole bool) bool {
+	if err != nil {
+		return false
+	}
+	defer zr.Close()
+
+	_, err = io.Copy(io.Discard, zr)
+	return err == nil || (!bWhole && err == io.ErrUnexpectedEOF)
+}
+
+// detectInputFormat returns the compression format of the head of the input, or nil if it is not compressed.
+func detectInputFormat(sample []byte) *inputFormat {
+	for _, f := range inputFormats {
+		if f.match(sample) {
+			return &f.format
+		}
+	}
+	return nil
+}
+
+// decompressInput returns the input decompressing in, if it is compressed with gzip, bzip2 or zlib.
+//
+// The size of the decompressed input is unknown, since the gzip trailer tells only the size of the last member
+// modulo 4 GiB. So it is spooled by openSizedInput if needed.
+func (g *GoSplit) decompressInput(in *input) (*input, g.Error) {
+	sample, gerr := peekSample(in)
+	if gerr != nil {
+		return nil, gerr
+	}
+	format := detectInputFormat(sample)
+	if format == nil {
+		return in, nil
+	}
+
+	zr, err := format.newReader(in.r)
+	if err != nil {
+		return nil, wrapper.Errorf("failed to decompress %s: %w", format.name, err)
+	}
+	fmt.Fprintf(g.wVerbose, "decompressing %s input\n", format.name)
+
+	return &input{
+		r:             zr,
+		size:          -1,
+		bDecompressed: true,
+		close: func() error {
+			zr.Close()
+			return in.Close()
+		},
+	}, nil
+}
+
+// peekSample returns the first inputSampleSize bytes of the input without consuming them.
+func peekSample(in *input) ([]byte, g.Error) {
+	if in.rs != nil {
+		sample := make([]byte, inputSampleSize)
+		n, err := io.ReadFull(in.rs, sample)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, wrapper.Errorf("failed to read: %w", err)
+		}
+		if _, err := in.rs.Seek(0, io.SeekStart); err != nil {
+			return nil, wrapper.Errorf("failed to seek: %w", err)
+		}
+		return sample[:n], nil
+	}
+
+	br := bufio.NewReader(in.r)
+	sample, err := br.Peek(inputSampleSize)
+	if err != nil && err != io.EOF {
+		return nil, wrapper.Errorf("failed to read: %w", err)
+	}
+	in.r = br
+	return sample, nil
+}
@@ -0,0 +1,230 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"testing"
+)
+
+func helperCompressFile(t *testing.T, format string, content []byte) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var zw io.WriteCloser
+	switch format {
+	case "gzip":
+		zw = gzip.NewWriter(&buf)
+	case "zlib":
+		zw = zlib.NewWriter(&buf)
+	default:
+		t.Fatalf("unknown format %#v", format)
+	}
+	if _, err := zw.Write(content); err != nil {
+		t.Fatal("failed to compress:", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal("failed to compress:", err)
+	}
+
+	filePath := path.Join(t.TempDir(), "input")
+	if err := os.WriteFile(filePath, buf.Bytes(), 0o644); err != nil {
+		t.Fatal("failed to write:", err)
+	}
+	return filePath
+}
+
+func helperJoinSink(t *testing.T, sink *gosplit.MemorySink) []byte {
+	t.Helper()
+
+	var joined []byte
+	for _, name := range sink.Names() {
+		data, _ := sink.Bytes(name)
+		joined = append(joined, data...)
+	}
+	return joined
+}
+
+func TestOptions_Decompress(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile("testdata/example.txt")
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+	inputs := map[string]string{
+		"gzip":  helperCompressFile(t, "gzip", content),
+		"zlib":  helperCompressFile(t, "zlib", content),
+		"bzip2": "testdata/example.txt.bz2",
+		"plain": "testdata/example.txt",
+	}
+	methods := map[string]struct {
+		split  func(g *gosplit.GoSplit) error
+		nFiles int
+	}{
+		"ByLines":      {func(g *gosplit.GoSplit) error { return g.ByLines(20) }, 3},
+		"ByBytes":      {func(g *gosplit.GoSplit) error { return g.ByBytes(500) }, 3},
+		"ByLineBytes":  {func(g *gosplit.GoSplit) error { return g.ByLineBytes(500) }, 4},
+		"ByNumber":     {func(g *gosplit.GoSplit) error { return g.ByNumber(4) }, 4},
+		"ByLineNumber": {func(g *gosplit.GoSplit) error { return g.ByLineNumber(4) }, 4},
+	}
+
+	for format, filePath := range inputs {
+		for method, tt := range methods {
+			format, filePath, method, tt := format, filePath, method, tt
+			t.Run(format+"/"+method, func(t *testing.T) {
+				t.Parallel()
+
+				sink := gosplit.NewMemorySink()
+				g := helperNew(t, filePath, "x", gosplit.Options{Sink: sink, Decompress: true})
+				if err := tt.split(g); err != nil {
+					t.Fatalf("%s() failed: %v", method, err)
+				}
+
+				if got := len(sink.Names()); got != tt.nFiles {
+					t.Errorf("len(Names()) = %#v, want %#v", got, tt.nFiles)
+				}
+				if got := helperJoinSink(t, sink); !bytes.Equal(got, content) {
+					t.Errorf("joined output files differ from the decompressed input")
+				}
+			})
+		}
+	}
+}
+
+func TestOptions_Decompress_Stdin(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile("testdata/example.txt")
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+	compressed, err := os.ReadFile(helperCompressFile(t, "gzip", content))
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+
+	// the size is not known from the stream, so that the input is spooled
+	sink := gosplit.NewMemorySink()
+	g, gerr := gosplit.NewReader(bytes.NewReader(compressed), "x", gosplit.Options{Sink: sink, Decompress: true})
+	if gerr != nil {
+		t.Fatal("NewReader() failed:", gerr)
+	}
+	if err := g.ByNumber(4); err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+
+	if got := len(sink.Names()); got != 4 {
+		t.Errorf("len(Names()) = %#v, want %#v", got, 4)
+	}
+	if got := helperJoinSink(t, sink); !bytes.Equal(got, content) {
+		t.Errorf("joined output files differ from the decompressed input")
+	}
+}
+
+func TestOptions_Decompress_MultipleMembers(t *testing.T) {
+	t.Parallel()
+
+	// the trailer of the last member tells only its own size
+	filePath := helperCompressFile(t, "gzip", []byte("a\nb\n"))
+	second, err := os.ReadFile(helperCompressFile(t, "gzip", []byte("c\n")))
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal("failed to open:", err)
+	}
+	if _, err := f.Write(second); err != nil {
+		t.Fatal("failed to write:", err)
+	}
+	f.Close()
+
+	sink := gosplit.NewMemorySink()
+	g := helperNew(t, filePath, "x", gosplit.Options{Sink: sink, Decompress: true})
+	if err := g.ByNumber(2); err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+	if got := sink.Names(); len(got) != 2 {
+		t.Errorf("Names() = %#v, want 2 files", got)
+	}
+	if got := string(helperJoinSink(t, sink)); got != "a\nb\nc\n" {
+		t.Errorf("joined output files = %#v, want %#v", got, "a\nb\nc\n")
+	}
+
+	// ByLines reads all members
+	sink = gosplit.NewMemorySink()
+	g = helperNew(t, filePath, "x", gosplit.Options{Sink: sink, Decompress: true})
+	if err := g.ByLines(2); err != nil {
+		t.Fatal("ByLines() failed:", err)
+	}
+	if got := string(helperJoinSink(t, sink)); got != "a\nb\nc\n" {
+		t.Errorf("joined output files = %#v, want %#v", got, "a\nb\nc\n")
+	}
+}
+
+func TestOptions_Decompress_Detect(t *testing.T) {
+	t.Parallel()
+
+	large := make([]byte, 100000)
+	rand.New(rand.NewSource(1)).Read(large)
+
+	cases := map[string]struct {
+		content     []byte
+		bCompressed bool
+	}{
+		// "HK" is also the valid header of zlib
+		"zlib-like text":  {[]byte("HKEY_LOCAL_MACHINE\\SOFTWARE\n"), false},
+		"zlib-like short": {[]byte("HK"), false},
+		// the sample ends in the middle of the compressed stream
+		"large zlib": {large, true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filePath := path.Join(t.TempDir(), "input")
+			if tt.bCompressed {
+				filePath = helperCompressFile(t, "zlib", tt.content)
+			} else if err := os.WriteFile(filePath, tt.content, 0o644); err != nil {
+				t.Fatal("failed to write:", err)
+			}
+
+			sink := gosplit.NewMemorySink()
+			g := helperNew(t, filePath, "x", gosplit.Options{Sink: sink, Decompress: true})
+			if err := g.ByLines(10); err != nil {
+				t.Fatal("ByLines() failed:", err)
+			}
+			if got := helperJoinSink(t, sink); !bytes.Equal(got, tt.content) {
+				t.Errorf("joined output files differ from the content")
+			}
+		})
+	}
+}
+
+func TestOptions_Decompress_Disabled(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt.bz2"
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+
+	sink := gosplit.NewMemorySink()
+	g := helperNew(t, filePath, "x", gosplit.Options{Sink: sink})
+	if err := g.ByNumber(2); err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+	if got := helperJoinSink(t, sink); !bytes.Equal(got, content) {
+		t.Errorf("joined output files differ from the compressed input")
+	}
+}
@@ -0,0 +1,40 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"errors"
+)
+
+// ErrGoSplit represents any errors in this package.
+var ErrGoSplit = errors.New("gosplit")
+
+// Specific errors.
+var (
+	ErrInvalidBytes        = errors.New("invalid number of bytes")
+	ErrInvalidLines        = errors.New("invalid number of lines")
+	ErrInvalidNumber       = errors.New("invalid number of chunks")
+	ErrInvalidChunk        = errors.New("invalid chunk number")
+	ErrInvalidSuffixLength = errors.New("invalid suffix length")
+	ErrInvalidSuffixStart  = errors.New("invalid suffix start")
+	ErrInvalidSuffix       = errors.New("invalid suffix")
+	ErrInvalidSeparator    = errors.New("invalid separator")
+	ErrInvalidCompression  = errors.New("invalid compression")
+	ErrInvalidSink         = errors.New("invalid sink")
+	ErrInvalidJobs         = errors.New("invalid number of jobs")
+	ErrUnknownSize         = errors.New("cannot determine file size")
+	ErrIsDirectory         = errors.New("is a directory")
+	ErrNoFreeSpace         = errors.New("no free space available")
+	ErrSuffixExhausted     = errors.New("output file suffixes exhausted")
+	ErrMissingChunk        = errors.New("missing output file")
+	ErrUnexpectedChunk     = errors.New("unexpected output file")
+	ErrTruncatedChunk      = errors.New("truncated output file")
+	ErrModifiedChunk       = errors.New("modified output file")
+	ErrFilterFailed        = errors.New("filter command failed")
+	ErrCannotResume        = errors.New("cannot resume split")
+	ErrJournalMismatch     = errors.New("journal does not match")
+	ErrInterrupted         = errors.New("interrupted")
+)
+
+// wrapper is a error wrapper for this package.
+var wrapper = g.NewWrapper(ErrGoSplit)
@@ -0,0 +1,56 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"fmt"
+	"log"
+	"os"
+)
+
+func Example() {
+	outDir, err := os.MkdirTemp("", "gosplit")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	g, err := gosplit.New("testdata/example.txt", "x", gosplit.Options{OutDir: outDir})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := g.ByLines(10); err != nil {
+		log.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, entry := range entries {
+		fmt.Println(entry.Name())
+	}
+	// Output:
+	// xaa
+	// xab
+	// xac
+	// xad
+	// xae
+}
+
+func ExampleGoSplit_ExtractByLineNumber() {
+	g, err := gosplit.New("testdata/example.txt", "x", gosplit.Options{Output: os.Stdout})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := g.ExtractByLineNumber(1, 8); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// # The Go Programming Language
+	//
+	// Go is an open source programming language that makes it easy to build simple,
+	// reliable, and efficient software.
+	//
+	// ![Gopher image](https://golang.org/doc/gopher/fiveyears.jpg)
+}
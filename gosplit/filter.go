@@ -0,0 +1,69 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"io"
+	"os"
+	"os/exec"
+)
+
+// filterWriter writes a chunk to the standard input of the filter command.
+type filterWriter struct {
+	cmd         *exec.Cmd
+	stdin       io.WriteCloser
+	outFilePath string
+	bBrokenPipe bool
+}
+
+// startFilter starts the filter command with the environment variable FILE set to outFilePath.
+func (g *GoSplit) startFilter(outFilePath string) (*filterWriter, g.Error) {
+	cmd := shellCommand(g.filterCommand)
+	cmd.Env = append(os.Environ(), "FILE="+outFilePath)
+	cmd.Stdout = g.wOut
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, wrapper.Errorf("failed to create pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, wrapper.Errorf("%w: %#v: %w", ErrFilterFailed, g.filterCommand, err)
+	}
+
+	return &filterWriter{cmd: cmd, stdin: stdin, outFilePath: outFilePath}, nil
+}
+
+// Write implements io.Writer.
+//
+// As the same as GNU split, the rest of the chunk is discarded when the command exits early.
+func (f *filterWriter) Write(p []byte) (int, error) {
+	if f.bBrokenPipe {
+		return len(p), nil
+	}
+
+	n, err := f.stdin.Write(p)
+	if err != nil && isBrokenPipe(err) {
+		f.bBrokenPipe = true
+		return len(p), nil
+	}
+	return n, err
+}
+
+// Close implements io.Closer, waiting for the command to exit.
+func (f *filterWriter) Close() error {
+	errClose := f.stdin.Close()
+
+	if err := f.cmd.Wait(); err != nil {
+		// the command killed by SIGPIPE is not an error as the same as GNU split
+		if isKilledByBrokenPipe(err) {
+			return nil
+		}
+		return wrapper.Errorf("%w: FILE=%#v: %w", ErrFilterFailed, f.outFilePath, err)
+	}
+
+	if errClose != nil && !f.bBrokenPipe {
+		return wrapper.Errorf("failed to close: %w", errClose)
+	}
+	return nil
+}
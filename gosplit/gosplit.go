@@ -0,0 +1,1202 @@
+// Package gosplit implements file splitting based on GNU coreutils' split behavior.
+//
+// The options are given as Options to New, and then one of the methods such as ByLines splits the file:
+//
+//	g, err := gosplit.New("input.txt", "x", gosplit.Options{OutDir: "out"})
+//	if err != nil {
+//		return err
+//	}
+//	if err := g.ByLines(1000); err != nil {
+//		return err
+//	}
+//
+// The errors can be checked with errors.Is against ErrGoSplit and the specific errors such as ErrInvalidBytes.
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+// GoSplit provides the methods for splitting the input, which is a file or io.Reader.
+type GoSplit struct {
+	filePath         string
+	r                io.Reader
+	ra               io.ReaderAt
+	raSize           int64
+	prefix           string
+	sink             Sink
+	wOut             io.Writer
+	wVerbose         io.Writer
+	wManifest        io.Writer
+	manifest         *manifestRecorder
+	filterCommand    string
+	separator        byte
+	suffix           Suffix
+	compression      Compression
+	compressLevel    int
+	bCountCompressed bool
+	bDecompress      bool
+	bElideEmptyFiles bool
+	jobs             int
+	bKeepPartial     bool
+	bResume          bool
+	journal          *journal
+	firstNumber      int      // the number of the first output file, which is not 0 when resuming
+	created          []string // the names of output files created in the sink by the current split
+	nCompleted       atomic.Int64
+	nDiscarded       atomic.Int64 // the number of incomplete output files discarded from the sink
+	ctxInterrupt     context.Context
+	interrupt        context.CancelFunc
+	ctx              context.Context // the context of the current split, which is nil without it
+	progressFunc     func(Progress)
+	progress         *progress
+	bSpool           bool
+	inputSize        int64
+}
+
+// New returns a new GoSplit struct splitting the file of filePath, or standard input if filePath is "-",
+// with validating opts.
+func New(filePath string, prefix string, opts Options) (*GoSplit, error) {
+	gs, gerr := newGoSplit(prefix, opts)
+	if gerr != nil {
+		return nil, gerr
+	}
+
+	gs.filePath = filePath
+	return gs, nil
+}
+
+// NewReader returns a new GoSplit struct splitting the content of r with validating opts.
+//
+// The size of r is known only when r is a regular file, which is required by ByNumber and the like.
+func NewReader(r io.Reader, prefix string, opts Options) (*GoSplit, error) {
+	gs, gerr := newGoSplit(prefix, opts)
+	if gerr != nil {
+		return nil, gerr
+	}
+
+	gs.r = r
+	return gs, nil
+}
+
+// NewReaderAt returns a new GoSplit struct splitting the content of r with size with validating opts.
+func NewReaderAt(r io.ReaderAt, size int64, prefix string, opts Options) (*GoSplit, error) {
+	if size < 0 {
+		return nil, wrapper.Errorf("%w: %#v", ErrInvalidBytes, size)
+	}
+
+	gs, gerr := newGoSplit(prefix, opts)
+	if gerr != nil {
+		return nil, gerr
+	}
+
+	gs.ra = r
+	gs.raSize = size
+	return gs, nil
+}
+
+// newGoSplit returns a new GoSplit struct without the input.
+func newGoSplit(prefix string, opts Options) (*GoSplit, g.Error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	gs := &GoSplit{
+		prefix:           prefix,
+		sink:             opts.Sink,
+		wOut:             os.Stdout,
+		wVerbose:         io.Discard,
+		wManifest:        opts.Manifest,
+		filterCommand:    opts.Filter,
+		separator:        '\n',
+		suffix:           opts.Suffix,
+		compression:      opts.Compress,
+		compressLevel:    opts.CompressLevel,
+		bCountCompressed: opts.CountCompressed && opts.Compress != CompressNone,
+		bDecompress:      opts.Decompress,
+		bElideEmptyFiles: opts.ElideEmptyFiles,
+		jobs:             opts.Jobs,
+		bKeepPartial:     opts.KeepPartial,
+		bResume:          opts.Resume,
+		bSpool:           opts.Spool,
+		inputSize:        opts.InputSize,
+		progressFunc:     opts.Progress,
+	}
+	if opts.Sink == nil {
+		gs.sink = NewDirSink(opts.OutDir)
+	}
+	gs.ctxInterrupt, gs.interrupt = context.WithCancel(context.Background())
+	if opts.Output != nil {
+		gs.wOut = opts.Output
+	}
+	if opts.Verbose != nil {
+		gs.wVerbose = opts.Verbose
+	}
+	if opts.Separator != "" {
+		gs.separator = opts.Separator[0]
+	}
+	return gs, nil
+}
+
+// ParseSize converts strSize to nBytes, e.g. "10K" -> 10 * 1024.
+func ParseSize(strSize string) (int64, error) {
+	re := regexp.MustCompile(`^(\d+)(b|(\w)(iB|B)?)?$`)
+	m := re.FindStringSubmatch(strSize)
+	if m == nil {
+		return 0, wrapper.Errorf("%w: %#v", ErrInvalidBytes, strSize)
+	}
+
+	x := new(big.Int)
+	x.SetString(m[1], 10)
+
+	var base int64
+	switch m[4] {
+	case "B":
+		base = 1000
+	case "iB":
+		base = 1024
+	default:
+		base = 1024
+	}
+
+	multiplier := new(big.Int)
+	switch m[2] {
+	case "":
+		multiplier.SetInt64(1)
+	case "b":
+		multiplier.SetInt64(512)
+	default:
+		exponentMap := map[string]int64{
+			"E": 6, "G": 3, "K": 1, "k": 1, "M": 2, "m": 2,
+			"P": 5, "Q": 10, "R": 9, "T": 4, "Y": 8, "Z": 7,
+		}
+		exponent, ok := exponentMap[m[3]]
+		if !ok {
+			return 0, wrapper.Errorf("%w: %#v", ErrInvalidBytes, strSize)
+		}
+		multiplier.Exp(big.NewInt(base), big.NewInt(exponent), nil)
+	}
+
+	x.Mul(x, multiplier)
+	if !x.IsInt64() {
+		return 0, wrapper.Errorf("%w: %#v: Value too large for defined data type", ErrInvalidBytes, strSize)
+	}
+
+	n := x.Int64()
+	if n <= 0 {
+		return 0, wrapper.Errorf("%w: %#v: Numerical result out of range", ErrInvalidBytes, strSize)
+	}
+
+	return n, nil
+}
+
+// ParseSeparator converts strSep to the separator of lines/records for Options, e.g. "," -> ",".
+//
+// The two characters `\0` are converted to the NUL character as the same as GNU split.
+func ParseSeparator(strSep string) (string, error) {
+	switch {
+	case strSep == "":
+		return "", wrapper.Errorf("%w: empty record separator", ErrInvalidSeparator)
+	case strSep == "\\0":
+		return "\x00", nil
+	case len(strSep) > 1:
+		return "", wrapper.Errorf("%w: %#v: multi-character separator", ErrInvalidSeparator, strSep)
+	}
+
+	return strSep, nil
+}
+
+// ChunkMode represents how the content is split into chunks with "-n" option.
+type ChunkMode int
+
+// Chunk modes.
+const (
+	// ChunkBytes splits into chunks based on size of input, e.g. "N" and "K/N".
+	ChunkBytes ChunkMode = iota
+	// ChunkLines splits into chunks without splitting lines, e.g. "l/N" and "l/K/N".
+	ChunkLines
+	// ChunkRoundRobin distributes lines into chunks in round robin, e.g. "r/N" and "r/K/N".
+	ChunkRoundRobin
+)
+
+// ParseChunks converts strChunks to the chunk mode, the chunk number k and the number of chunks n,
+// e.g. "l/2/4" -> ChunkLines, 2, 4.
+//
+// k is 0 when strChunks has no chunk number, e.g. "4" -> ChunkBytes, 0, 4.
+func ParseChunks(strChunks string) (ChunkMode, int, int, error) {
+	re := regexp.MustCompile(`^(?:([lr])/)?(?:(\d+)/)?(\d+)$`)
+	m := re.FindStringSubmatch(strChunks)
+	if m == nil {
+		return 0, 0, 0, wrapper.Errorf("%w: %#v", ErrInvalidNumber, strChunks)
+	}
+
+	var mode ChunkMode
+	switch m[1] {
+	case "l":
+		mode = ChunkLines
+	case "r":
+		mode = ChunkRoundRobin
+	default:
+		mode = ChunkBytes
+	}
+
+	n, err := strconv.Atoi(m[3])
+	if err != nil || n <= 0 {
+		return 0, 0, 0, wrapper.Errorf("%w: %#v", ErrInvalidNumber, strChunks)
+	}
+
+	k := 0
+	if m[2] != "" {
+		k, err = strconv.Atoi(m[2])
+		if err != nil || k <= 0 || k > n {
+			return 0, 0, 0, wrapper.Errorf("%w: %#v", ErrInvalidChunk, strChunks)
+		}
+	}
+
+	return mode, k, n, nil
+}
+
+// ByLines splits the input by nLines.
+func (g *GoSplit) ByLines(nLines int) error {
+	if nLines <= 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidLines, nLines)
+	}
+
+	in, gerr := g.openInput()
+	if gerr != nil {
+		return gerr
+	}
+	defer in.Close()
+
+	if err := g.checkFileSize(in); err != nil {
+		return err
+	}
+
+	if err := g.startJournal(in, "lines", int64(nLines)); err != nil {
+		return err
+	}
+
+	g.startProgress(in.size)
+
+	if err := g.finishOutput(g.doByLines(in.r, nLines)); err != nil {
+		return err
+	}
+
+	return g.writeManifest()
+}
+
+// ByNumber splits the input into nNumber files.
+func (g *GoSplit) ByNumber(nNumber int) error {
+	if nNumber <= 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidNumber, nNumber)
+	}
+	if g.bResume {
+		return wrapper.Errorf("%w: by number", ErrCannotResume)
+	}
+
+	in, gerr := g.openSizedInput(false)
+	if gerr != nil {
+		return gerr
+	}
+	defer in.Close()
+
+	if err := g.checkFileSize(in); err != nil {
+		return err
+	}
+
+	g.startProgress(in.size)
+
+	gerr = g.doByNumber(in.r, in.size, nNumber)
+	if gerr == nil {
+		gerr = in.checkStreamSize()
+	}
+	if err := g.finishOutput(gerr); err != nil {
+		return err
+	}
+
+	return g.writeManifest()
+}
+
+// ExtractByNumber outputs the k-th chunk of nNumber chunks of the input to the output writer.
+//
+// k starts at 1 as the same as "-n K/N" option.
+func (g *GoSplit) ExtractByNumber(k int, nNumber int) error {
+	if nNumber <= 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidNumber, nNumber)
+	}
+	if k <= 0 || k > nNumber {
+		return wrapper.Errorf("%w: %#v", ErrInvalidChunk, k)
+	}
+
+	in, gerr := g.openSizedInput(true)
+	if gerr != nil {
+		return gerr
+	}
+	defer in.Close()
+
+	// no need to check disk free space because no output file is created
+
+	if err := g.doExtractByNumber(in.rs, in.size, k, nNumber); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ByLineNumber splits the input into nNumber files without splitting lines.
+func (g *GoSplit) ByLineNumber(nNumber int) error {
+	if nNumber <= 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidNumber, nNumber)
+	}
+	if g.bResume {
+		return wrapper.Errorf("%w: by number", ErrCannotResume)
+	}
+
+	in, gerr := g.openSizedInput(true)
+	if gerr != nil {
+		return gerr
+	}
+	defer in.Close()
+
+	if err := g.checkFileSize(in); err != nil {
+		return err
+	}
+
+	g.startProgress(in.size)
+
+	if err := g.finishOutput(g.doByLineNumber(in.rs, in.size, nNumber)); err != nil {
+		return err
+	}
+
+	return g.writeManifest()
+}
+
+// ExtractByLineNumber outputs the k-th chunk of nNumber chunks of the input to the output writer
+// without splitting lines.
+//
+// k starts at 1 as the same as "-n l/K/N" option.
+func (g *GoSplit) ExtractByLineNumber(k int, nNumber int) error {
+	if nNumber <= 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidNumber, nNumber)
+	}
+	if k <= 0 || k > nNumber {
+		return wrapper.Errorf("%w: %#v", ErrInvalidChunk, k)
+	}
+
+	in, gerr := g.openSizedInput(true)
+	if gerr != nil {
+		return gerr
+	}
+	defer in.Close()
+
+	// no need to check disk free space because no output file is created
+
+	if err := g.doExtractByLineNumber(in.rs, in.size, k, nNumber); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ByRoundRobin distributes the lines of the input into nNumber files in round robin.
+//
+// All the output files are kept open until the end, so nNumber is limited by the open file limit of the process,
+// e.g. "ulimit -n". It fails with ErrSuffixExhausted before reading the input if the suffixes are not enough.
+func (g *GoSplit) ByRoundRobin(nNumber int) error {
+	if nNumber <= 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidNumber, nNumber)
+	}
+	if g.bResume {
+		return wrapper.Errorf("%w: in round robin", ErrCannotResume)
+	}
+
+	in, gerr := g.openInput()
+	if gerr != nil {
+		return gerr
+	}
+	defer in.Close()
+
+	if err := g.checkFileSize(in); err != nil {
+		return err
+	}
+
+	g.startProgress(in.size)
+
+	if err := g.finishOutput(g.doByRoundRobin(in.r, nNumber)); err != nil {
+		return err
+	}
+
+	return g.writeManifest()
+}
+
+// ExtractByRoundRobin outputs the k-th chunk of the lines of the input distributed into nNumber chunks
+// in round robin to the output writer.
+//
+// k starts at 1 as the same as "-n r/K/N" option.
+func (g *GoSplit) ExtractByRoundRobin(k int, nNumber int) error {
+	if nNumber <= 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidNumber, nNumber)
+	}
+	if k <= 0 || k > nNumber {
+		return wrapper.Errorf("%w: %#v", ErrInvalidChunk, k)
+	}
+
+	in, gerr := g.openInput()
+	if gerr != nil {
+		return gerr
+	}
+	defer in.Close()
+
+	// no need to check disk free space because no output file is created
+
+	if err := g.doExtractByRoundRobin(in.r, k, nNumber); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ByBytes splits the input by nBytes.
+func (g *GoSplit) ByBytes(nBytes int64) error {
+	if nBytes <= 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidBytes, nBytes)
+	}
+
+	in, gerr := g.openInput()
+	if gerr != nil {
+		return gerr
+	}
+	defer in.Close()
+
+	if err := g.checkFileSize(in); err != nil {
+		return err
+	}
+
+	if err := g.startJournal(in, "bytes", nBytes); err != nil {
+		return err
+	}
+
+	g.startProgress(in.size)
+
+	if err := g.finishOutput(g.doByBytes(in.r, in.size, nBytes)); err != nil {
+		return err
+	}
+
+	return g.writeManifest()
+}
+
+// ByLineBytes splits the input by at most nBytes without splitting lines.
+//
+// As the same as GNU split, a line longer than nBytes is split into nBytes pieces.
+func (g *GoSplit) ByLineBytes(nBytes int64) error {
+	if nBytes <= 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidBytes, nBytes)
+	}
+
+	in, gerr := g.openInput()
+	if gerr != nil {
+		return gerr
+	}
+	defer in.Close()
+
+	if err := g.checkFileSize(in); err != nil {
+		return err
+	}
+
+	if err := g.startJournal(in, "line-bytes", nBytes); err != nil {
+		return err
+	}
+
+	g.startProgress(in.size)
+
+	if err := g.finishOutput(g.doByLineBytes(in.r, nBytes)); err != nil {
+		return err
+	}
+
+	return g.writeManifest()
+}
+
+// checkFileSize checks disk free space for output files of the input.
+//
+// The check is skipped when the size of the input is unknown.
+// With compression, the size of output files is estimated by compressing the head of the input.
+func (g *GoSplit) checkFileSize(in *input) g.Error {
+	if in.size < 0 {
+		return nil
+	}
+
+	// output files are not always written to the disk with the filter command or other sinks
+	if g.filterCommand != "" {
+		return nil
+	}
+	ds, ok := g.sink.(*DirSink)
+	if !ok {
+		return nil
+	}
+
+	fileSize := in.size
+	if g.compression != CompressNone {
+		estimated, gerr := g.estimateCompressedSize(in)
+		if gerr != nil {
+			return gerr
+		}
+		fileSize = estimated
+	}
+
+	dir := ds.dir
+	if dir == "" {
+		dir = "./"
+	}
+	freeBytesAvailable, gerr := getDiskFreeSpace(dir)
+	if gerr != nil {
+		return gerr
+	}
+
+	if uint64(fileSize) > freeBytesAvailable {
+		return wrapper.Errorf("%w", ErrNoFreeSpace)
+	}
+	return nil
+}
+
+// generateOutFileName returns n-th output file name with prefix, and the extension of the compression.
+func (g *GoSplit) generateOutFileName(number int) (string, g.Error) {
+	suffix, gerr := g.suffix.generate(number)
+	if gerr != nil {
+		return "", gerr
+	}
+
+	return g.prefix + suffix + g.compression.Extension(), nil
+}
+
+// generateOutFilePath returns n-th output file path, which is the name itself unless the sink is a directory.
+func (g *GoSplit) generateOutFilePath(number int) (string, g.Error) {
+	outFileName, gerr := g.generateOutFileName(number)
+	if gerr != nil {
+		return "", gerr
+	}
+
+	if ds, ok := g.sink.(*DirSink); ok {
+		return ds.Path(outFileName), nil
+	}
+	return outFileName, nil
+}
+
+// createOutFile creates n-th output file in the sink, or starts the filter command for it,
+// compressing the content if needed.
+func (g *GoSplit) createOutFile(number int) (io.WriteCloser, g.Error) {
+	if g.journal != nil {
+		if gerr := g.journal.commit(); gerr != nil {
+			return nil, gerr
+		}
+	}
+	if gerr := g.checkInterrupted(); gerr != nil {
+		return nil, gerr
+	}
+	number += g.firstNumber
+
+	outFilePath, gerr := g.generateOutFilePath(number)
+	if gerr != nil {
+		return nil, gerr
+	}
+
+	outFileName, gerr := g.generateOutFileName(number)
+	if gerr != nil {
+		return nil, gerr
+	}
+
+	var w io.WriteCloser
+	if g.filterCommand != "" {
+		fmt.Fprintf(g.wVerbose, "executing with FILE=%#v\n", outFilePath)
+		wFilter, gerr := g.startFilter(outFilePath)
+		if gerr != nil {
+			return nil, gerr
+		}
+		w = wFilter
+	} else {
+		wSink, err := g.sink.Create(outFileName)
+		if err != nil {
+			return nil, wrapper.Errorf("%w", err)
+		}
+		fmt.Fprintf(g.wVerbose, "creating file %#v\n", outFilePath)
+		g.created = append(g.created, outFileName)
+		w = wSink
+	}
+
+	w = g.interruptOutFile(w)
+
+	if g.compression != CompressNone {
+		cw, gerr := g.newCompressWriter(w)
+		if gerr != nil {
+			abortOutFile(w)
+			return nil, gerr
+		}
+		w = cw
+	}
+
+	w = g.progressOutFile(w, number)
+	return g.journalOutFile(g.trackOutFile(w, outFileName, number), outFileName, number), nil
+}
+
+// doByLines splits the content from io.Reader by nLines.
+//
+// The lines are copied as they are in bounded memory, so that the output files reproduce the input when concatenated.
+func (g *GoSplit) doByLines(r io.Reader, nLines int) g.Error {
+	r = g.trackInput(r, true)
+	br := bufio.NewReader(r)
+	for i := 0; ; i++ {
+		// create the file only when the content is left
+		if _, err := br.Peek(1); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return wrapper.Errorf("failed to read: %w", err)
+		}
+
+		w, gerr := g.createOutFile(i)
+		if gerr != nil {
+			return gerr
+		}
+
+		bw := bufio.NewWriter(w)
+		for j := 0; j < nLines; j++ {
+			if _, err := copyLine(bw, br, g.separator); err != nil {
+				if err == io.EOF {
+					break
+				}
+				abortOutFile(w)
+				return wrapper.Errorf("failed to write: %w", err)
+			}
+		}
+		if err := bw.Flush(); err != nil {
+			abortOutFile(w)
+			return wrapper.Errorf("failed to write: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return wrapper.Errorf("failed to close: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// chunkRange returns the byte range [start, end) of the i-th chunk (0-origin) of nNumber chunks.
+//
+// As the same as GNU split, the remainder goes to the last chunk, and the chunks are of 1 byte
+// followed by the empty ones if fileSize is less than nNumber.
+func chunkRange(fileSize int64, i int, nNumber int) (int64, int64) {
+	n := min(int64(nNumber), fileSize)
+	if int64(i) >= n {
+		return fileSize, fileSize
+	}
+	chunkSize := fileSize / n
+
+	start := int64(i) * chunkSize
+	if int64(i) == n-1 {
+		return start, fileSize
+	}
+	return start, start + chunkSize
+}
+
+// lineChunkEnd returns the end offset of the i-th chunk (0-origin) of nNumber chunks
+// without splitting lines.
+//
+// As the same as GNU split, the chunk ends at the end of the line containing its last byte,
+// so the chunk becomes empty when the previous one has already reached its end.
+func lineChunkEnd(r io.ReadSeeker, sep byte, fileSize int64, i int, nNumber int) (int64, g.Error) {
+	_, end := chunkRange(fileSize, i, nNumber)
+	if end == 0 || end == fileSize {
+		return end, nil
+	}
+
+	// seek to the last byte of the chunk and scan forward for the end of the line
+	offset := end - 1
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return 0, wrapper.Errorf("failed to seek: %w", err)
+	}
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadSlice(sep)
+		offset += int64(len(line))
+		if err == nil {
+			return offset, nil
+		}
+		if err == io.EOF {
+			return fileSize, nil
+		}
+		if err != bufio.ErrBufferFull {
+			return 0, wrapper.Errorf("failed to read: %w", err)
+		}
+	}
+}
+
+// chunkEnds gives the end offsets of n chunks one by one, so that they are not held at once for a large n.
+type chunkEnds struct {
+	n        int
+	fileSize int64
+	end      func(i int) (int64, g.Error)
+}
+
+// numberEnds returns chunkEnds of nNumber chunks of the input of fileSize.
+func numberEnds(fileSize int64, nNumber int) chunkEnds {
+	return chunkEnds{n: nNumber, fileSize: fileSize, end: func(i int) (int64, g.Error) {
+		_, end := chunkRange(fileSize, i, nNumber)
+		return end, nil
+	}}
+}
+
+// lineNumberEnds returns chunkEnds of nNumber chunks of the input of fileSize without splitting lines.
+//
+// The end offsets are found by seeking r, which is restored to the current offset after that.
+func lineNumberEnds(r io.ReadSeeker, sep byte, fileSize int64, nNumber int) chunkEnds {
+	return chunkEnds{n: nNumber, fileSize: fileSize, end: func(i int) (int64, g.Error) {
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, wrapper.Errorf("failed to seek: %w", err)
+		}
+		end, gerr := lineChunkEnd(r, sep, fileSize, i, nNumber)
+		if gerr != nil {
+			return 0, gerr
+		}
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return 0, wrapper.Errorf("failed to seek: %w", err)
+		}
+		return end, nil
+	}}
+}
+
+// bytesEnds returns chunkEnds of the chunks of nBytes from the input of fileSize.
+func bytesEnds(fileSize int64, nBytes int64) chunkEnds {
+	n := fileSize / nBytes
+	if fileSize%nBytes != 0 {
+		n++
+	}
+	return chunkEnds{n: int(n), fileSize: fileSize, end: func(i int) (int64, g.Error) {
+		start := int64(i) * nBytes
+		return start + min(nBytes, fileSize-start), nil
+	}}
+}
+
+// doByNumber splits the content from io.Reader into nNumber files.
+func (g *GoSplit) doByNumber(r io.Reader, fileSize int64, nNumber int) g.Error {
+	return g.doByBoundaries(r, numberEnds(fileSize, nNumber))
+}
+
+// doByLineNumber splits the content from io.ReadSeeker into nNumber files without splitting lines.
+func (g *GoSplit) doByLineNumber(r io.ReadSeeker, fileSize int64, nNumber int) g.Error {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return wrapper.Errorf("failed to seek: %w", err)
+	}
+
+	return g.doByBoundaries(r, lineNumberEnds(r, g.separator, fileSize, nNumber))
+}
+
+// doByBoundaries splits the content from io.Reader at the offset 0 into files at the end offsets of ends.
+func (g *GoSplit) doByBoundaries(r io.Reader, ends chunkEnds) g.Error {
+	if ra, ok := g.concurrentReaderAt(r, ends.fileSize); ok {
+		return g.doByBoundariesAt(ra, ends)
+	}
+
+	r = g.trackInput(r, true)
+	nFiles := 0
+	for i, start := 0, int64(0); i < ends.n; i++ {
+		// the rest of the chunks are empty
+		if g.bElideEmptyFiles && start >= ends.fileSize {
+			break
+		}
+		end, gerr := ends.end(i)
+		if gerr != nil {
+			return gerr
+		}
+		chunkSize := end - start
+		start = end
+		if g.bElideEmptyFiles && chunkSize == 0 {
+			continue
+		}
+
+		w, gerr := g.createOutFile(nFiles)
+		if gerr != nil {
+			return gerr
+		}
+		nFiles++
+
+		written, err := io.CopyN(w, r, chunkSize)
+		if err != nil && err != io.EOF {
+			abortOutFile(w)
+			return wrapper.Errorf("failed to write: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return wrapper.Errorf("failed to close: %w", err)
+		}
+
+		// the file may be truncated after stat
+		if written < chunkSize {
+			break
+		}
+	}
+
+	return nil
+}
+
+// doExtractByNumber outputs the k-th chunk (1-origin) of nNumber chunks from io.ReadSeeker.
+func (g *GoSplit) doExtractByNumber(r io.ReadSeeker, fileSize int64, k int, nNumber int) g.Error {
+	start, end := chunkRange(fileSize, k-1, nNumber)
+	return g.doExtract(r, start, end)
+}
+
+// doExtractByLineNumber outputs the k-th chunk (1-origin) of nNumber chunks from io.ReadSeeker
+// without splitting lines.
+func (g *GoSplit) doExtractByLineNumber(r io.ReadSeeker, fileSize int64, k int, nNumber int) g.Error {
+	start := int64(0)
+	if k > 1 {
+		end, gerr := lineChunkEnd(r, g.separator, fileSize, k-2, nNumber)
+		if gerr != nil {
+			return gerr
+		}
+		start = end
+	}
+	end, gerr := lineChunkEnd(r, g.separator, fileSize, k-1, nNumber)
+	if gerr != nil {
+		return gerr
+	}
+
+	return g.doExtract(r, start, end)
+}
+
+// doExtract outputs the byte range [start, end) from io.ReadSeeker.
+func (g *GoSplit) doExtract(r io.ReadSeeker, start int64, end int64) g.Error {
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return wrapper.Errorf("failed to seek: %w", err)
+	}
+
+	// the file may be truncated after stat, then output the rest as GNU split does
+	if _, err := io.CopyN(g.interruptOutput(g.wOut), r, end-start); err != nil && err != io.EOF {
+		return wrapper.Errorf("failed to write: %w", err)
+	}
+
+	return nil
+}
+
+// copyLine copies a line including the trailing separator from bufio.Reader to io.Writer.
+//
+// It returns io.EOF only when no byte is left.
+func copyLine(w io.Writer, br *bufio.Reader, sep byte) (int64, error) {
+	var written int64
+	for {
+		line, err := br.ReadSlice(sep)
+		if len(line) > 0 {
+			n, werr := w.Write(line)
+			written += int64(n)
+			if werr != nil {
+				return written, werr
+			}
+		}
+
+		switch {
+		case err == nil:
+			return written, nil
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && written > 0:
+			return written, nil
+		default:
+			return written, err
+		}
+	}
+}
+
+// doByRoundRobin distributes the lines from io.Reader into nNumber files in round robin.
+func (g *GoSplit) doByRoundRobin(r io.Reader, nNumber int) (gerr g.Error) {
+	if _, gerr := g.generateOutFileName(g.firstNumber + nNumber - 1); gerr != nil {
+		return gerr
+	}
+
+	r = g.trackInput(r, false)
+	// the output files are held only when they are created, since nNumber may be much larger than the lines
+	ws := map[int]io.WriteCloser{}
+	bws := map[int]*bufio.Writer{}
+	defer func() {
+		indexes := make([]int, 0, len(ws))
+		for i := range ws {
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+		for _, i := range indexes {
+			w := ws[i]
+			// all files are incomplete after an error
+			if gerr != nil {
+				abortOutFile(w)
+				continue
+			}
+			if err := bws[i].Flush(); err != nil {
+				abortOutFile(w)
+				gerr = wrapper.Errorf("failed to write: %w", err)
+				continue
+			}
+			if err := w.Close(); err != nil {
+				gerr = wrapper.Errorf("failed to close: %w", err)
+			}
+		}
+	}()
+
+	create := func(i int) error {
+		w, gerr := g.createOutFile(i)
+		if gerr != nil {
+			return gerr
+		}
+		ws[i] = w
+		bws[i] = bufio.NewWriter(w)
+		return nil
+	}
+
+	// all files are created at once, or created on demand when empty files are elided
+	if !g.bElideEmptyFiles {
+		for i := 0; i < nNumber; i++ {
+			if err := create(i); err != nil {
+				return wrapper.Errorf("%w", err)
+			}
+		}
+	}
+
+	br := bufio.NewReader(r)
+	for i := 0; ; i = (i + 1) % nNumber {
+		if _, err := br.Peek(1); err == io.EOF {
+			break
+		}
+		if ws[i] == nil {
+			if err := create(i); err != nil {
+				return wrapper.Errorf("%w", err)
+			}
+		}
+
+		if _, err := copyLine(bws[i], br, g.separator); err != nil {
+			return wrapper.Errorf("failed to write: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// doExtractByRoundRobin outputs the k-th chunk (1-origin) of the lines from io.Reader
+// distributed into nNumber chunks in round robin.
+func (g *GoSplit) doExtractByRoundRobin(r io.Reader, k int, nNumber int) g.Error {
+	bw := bufio.NewWriter(g.interruptOutput(g.wOut))
+
+	br := bufio.NewReader(r)
+	for i := 0; ; i = (i + 1) % nNumber {
+		w := io.Discard
+		if i == k-1 {
+			w = bw
+		}
+
+		if _, err := copyLine(w, br, g.separator); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return wrapper.Errorf("failed to write: %w", err)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return wrapper.Errorf("failed to write: %w", err)
+	}
+
+	return nil
+}
+
+// doByBytes splits the content from io.Reader by nBytes. fileSize is -1 if unknown.
+func (g *GoSplit) doByBytes(r io.Reader, fileSize int64, nBytes int64) g.Error {
+	if ra, ok := g.concurrentReaderAt(r, fileSize); ok && !g.bCountCompressed {
+		return g.doByBoundariesAt(ra, bytesEnds(fileSize, nBytes))
+	}
+
+	r = g.trackInput(r, true)
+	br := bufio.NewReader(r)
+	for i := 0; ; i++ {
+		// create the file only when the content is left
+		if _, err := br.Peek(1); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return wrapper.Errorf("failed to read: %w", err)
+		}
+
+		w, gerr := g.createOutFile(i)
+		if gerr != nil {
+			return gerr
+		}
+
+		var err error
+		if g.bCountCompressed {
+			err = copyCompressed(w, br, nBytes)
+		} else {
+			_, err = io.CopyN(w, br, nBytes)
+		}
+		if err != nil && err != io.EOF {
+			abortOutFile(w)
+			return wrapper.Errorf("failed to write: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return wrapper.Errorf("failed to close: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// doByLineBytes splits the content from io.Reader by at most nBytes without splitting lines.
+func (g *GoSplit) doByLineBytes(r io.Reader, nBytes int64) (gerr g.Error) {
+	r = g.trackInput(r, true)
+	var (
+		w       io.WriteCloser
+		bw      *bufio.Writer
+		nFiles  int
+		written int64 // the input bytes written to the current file
+		// measured is the compressed size of the current file including compressOverhead when written was measuredAt
+		measured   int64 = compressOverhead
+		measuredAt int64
+	)
+	closeFile := func() error {
+		if w == nil {
+			return nil
+		}
+		defer func() {
+			w = nil
+			written = 0
+			measured = compressOverhead
+			measuredAt = 0
+		}()
+		if err := bw.Flush(); err != nil {
+			abortOutFile(w)
+			return wrapper.Errorf("failed to write: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return wrapper.Errorf("failed to close: %w", err)
+		}
+		return nil
+	}
+	write := func(b []byte) error {
+		if w == nil {
+			wNext, gerr := g.createOutFile(nFiles)
+			if gerr != nil {
+				return gerr
+			}
+			nFiles++
+			w = wNext
+			bw = bufio.NewWriter(w)
+		}
+		n, err := bw.Write(b)
+		written += int64(n)
+		if err != nil {
+			return wrapper.Errorf("failed to write: %w", err)
+		}
+		return nil
+	}
+	// measure updates the compressed size, which is mostly less than the bound of the input bytes since the last one
+	measure := func() (bool, error) {
+		if !g.bCountCompressed || w == nil || measuredAt == written {
+			return false, nil
+		}
+		if err := bw.Flush(); err != nil {
+			return false, wrapper.Errorf("failed to write: %w", err)
+		}
+		size, err := outFileSize(w)
+		if err != nil {
+			return false, wrapper.Errorf("failed to write: %w", err)
+		}
+		measured = size
+		measuredAt = written
+		return true, nil
+	}
+	// rest returns the input bytes which can be written to the current file
+	rest := func() int64 {
+		if !g.bCountCompressed {
+			return nBytes - written
+		}
+		room := maxCompressRoom(nBytes-measured) - (written - measuredAt)
+		if written == 0 {
+			// nBytes is too small for the overhead
+			room = max(room, min(nBytes, minCompressRoom))
+		}
+		return room
+	}
+	defer func() {
+		if gerr != nil {
+			// the current file is incomplete
+			if w != nil {
+				abortOutFile(w)
+			}
+			return
+		}
+		if err := closeFile(); err != nil {
+			gerr = wrapper.Errorf("%w", err)
+		}
+	}()
+
+	// hold keeps the bytes of the current line until it is decided which file they go to,
+	// so that it never grows much larger than nBytes
+	var hold []byte
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadSlice(g.separator)
+		if err != nil && err != bufio.ErrBufferFull && err != io.EOF {
+			return wrapper.Errorf("failed to read: %w", err)
+		}
+		hold = append(hold, line...)
+		endOfLine := err == nil || err == io.EOF
+
+		for len(hold) > 0 {
+			rest := rest()
+			if int64(len(hold)) <= rest {
+				if endOfLine {
+					if err := write(hold); err != nil {
+						return wrapper.Errorf("%w", err)
+					}
+					hold = hold[:0]
+				}
+				break
+			}
+
+			// the line may fit after compressing the current file
+			if bMeasuredNow, err := measure(); err != nil {
+				return wrapper.Errorf("%w", err)
+			} else if bMeasuredNow {
+				continue
+			}
+
+			// the line does not fit in the current file, then move to the next file
+			if written > 0 {
+				if err := closeFile(); err != nil {
+					return wrapper.Errorf("%w", err)
+				}
+				continue
+			}
+
+			// the line is longer than nBytes, then split it
+			if err := write(hold[:rest]); err != nil {
+				return wrapper.Errorf("%w", err)
+			}
+			hold = append(hold[:0], hold[rest:]...)
+			if err := closeFile(); err != nil {
+				return wrapper.Errorf("%w", err)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,7 @@
 package gosplit_test
 
 import (
-	"inaz2/GoSplit/internal/gosplit"
+	"inaz2/GoSplit/gosplit"
 
 	"flag"
 	"testing"
@@ -22,9 +22,11 @@ func targetTestByLines_Golden(dir string) error {
 	prefix := "TestByLines_Golden-"
 	nLines := 10
 
-	g := gosplit.New(filePath, prefix)
-	g.SetOutDir(dir)
-	err := g.ByLines(nLines)
+	g, err := gosplit.New(filePath, prefix, gosplit.Options{OutDir: dir})
+	if err != nil {
+		return err
+	}
+	err = g.ByLines(nLines)
 	if err != nil {
 		return err
 	}
@@ -37,9 +39,11 @@ func targetTestByNumber_Golden(dir string) error {
 	prefix := "TestByNumber_Golden-"
 	nNumber := 4
 
-	g := gosplit.New(filePath, prefix)
-	g.SetOutDir(dir)
-	err := g.ByNumber(nNumber)
+	g, err := gosplit.New(filePath, prefix, gosplit.Options{OutDir: dir})
+	if err != nil {
+		return err
+	}
+	err = g.ByNumber(nNumber)
 	if err != nil {
 		return err
 	}
@@ -52,9 +56,28 @@ func targetTestByNumber_EmptyFile_Golden(dir string) error {
 	prefix := "TestByNumber_EmptyFile_Golden-"
 	nNumber := 4
 
-	g := gosplit.New(filePath, prefix)
-	g.SetOutDir(dir)
-	err := g.ByNumber(nNumber)
+	g, err := gosplit.New(filePath, prefix, gosplit.Options{OutDir: dir})
+	if err != nil {
+		return err
+	}
+	err = g.ByNumber(nNumber)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func targetTestByLineNumber_Golden(dir string) error {
+	filePath := "testdata/example.txt"
+	prefix := "TestByLineNumber_Golden-"
+	nNumber := 4
+
+	g, err := gosplit.New(filePath, prefix, gosplit.Options{OutDir: dir})
+	if err != nil {
+		return err
+	}
+	err = g.ByLineNumber(nNumber)
 	if err != nil {
 		return err
 	}
@@ -67,9 +90,11 @@ func targetTestByBytes_Golden(dir string) error {
 	prefix := "TestByBytes_Golden-"
 	nBytes := int64(512)
 
-	g := gosplit.New(filePath, prefix)
-	g.SetOutDir(dir)
-	err := g.ByBytes(nBytes)
+	g, err := gosplit.New(filePath, prefix, gosplit.Options{OutDir: dir})
+	if err != nil {
+		return err
+	}
+	err = g.ByBytes(nBytes)
 	if err != nil {
 		return err
 	}
@@ -113,6 +138,18 @@ func TestByNumber_EmptyFile_Golden(t *testing.T) {
 	}
 }
 
+func TestByLineNumber_Golden(t *testing.T) {
+	dir := t.TempDir()
+	if err := targetTestByLineNumber_Golden(dir); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	got := golden.Txtar(t, dir)
+	if diff := golden.Check(t, flagUpdate, "testdata", "TestByLineNumber_Golden", got); diff != "" {
+		t.Error(diff)
+	}
+}
+
 func TestByBytes_Golden(t *testing.T) {
 	dir := t.TempDir()
 	if err := targetTestByBytes_Golden(dir); err != nil {
@@ -0,0 +1,1491 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func helperNew(t *testing.T, filePath string, prefix string, opts gosplit.Options) *gosplit.GoSplit {
+	t.Helper()
+
+	g, err := gosplit.New(filePath, prefix, opts)
+	if err != nil {
+		t.Fatal("New() failed:", err)
+	}
+	return g
+}
+
+func helperCountLines(t *testing.T, outDir string, filePath string) int {
+	t.Helper()
+
+	f, err := os.Open(path.Join(outDir, filePath))
+	if err != nil {
+		t.Fatal("failed to open:", err)
+	}
+	defer f.Close()
+
+	nLines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		nLines++
+	}
+	return nLines
+}
+
+func helperCountBytes(t *testing.T, outDir string, filePath string) int64 {
+	t.Helper()
+
+	fileInfo, err := os.Stat(path.Join(outDir, filePath))
+	if err != nil {
+		t.Fatal("failed to stat:", err)
+	}
+
+	fileSize := fileInfo.Size()
+	return fileSize
+}
+
+func TestByLines(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestByLines-"
+	outDir := t.TempDir()
+	nLines := 10
+	outFiles := []struct {
+		name   string
+		nLines int
+	}{
+		{prefix + "aa", 10},
+		{prefix + "ab", 10},
+		{prefix + "ac", 10},
+		{prefix + "ad", 10},
+		{prefix + "ae", 2},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByLines(nLines)
+	if err != nil {
+		t.Fatal("ByLines() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountLines(t, outDir, outFile.name)
+		if result != outFile.nLines {
+			t.Errorf("helperCountLines(%#v) = %#v, want %#v", outFile.name, result, outFile.nLines)
+		}
+	}
+}
+
+func TestByLines_ByteExact(t *testing.T) {
+	t.Parallel()
+
+	longLine := strings.Repeat("x", 100*1024) + "\n"
+	cases := map[string]struct {
+		input     string
+		separator string
+		nLines    int
+		want      []string
+	}{
+		"long line":            {longLine + "a\n" + longLine, "", 1, []string{longLine, "a\n", longLine}},
+		"crlf":                 {"a\r\nb\r\nc\r\n", "", 2, []string{"a\r\nb\r\n", "c\r\n"}},
+		"no final newline":     {"a\nb\nc", "", 2, []string{"a\nb\n", "c"}},
+		"nul separator":        {"a\x00b\nc\x00d", "\x00", 1, []string{"a\x00", "b\nc\x00", "d"}},
+		"lone carriage return": {"a\rb\n\r", "", 1, []string{"a\rb\n", "\r"}},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sink := gosplit.NewMemorySink()
+			g, gerr := gosplit.NewReader(strings.NewReader(tt.input), "x", gosplit.Options{Sink: sink, Separator: tt.separator})
+			if gerr != nil {
+				t.Fatal("NewReader() failed:", gerr)
+			}
+			if err := g.ByLines(tt.nLines); err != nil {
+				t.Fatal("ByLines() failed:", err)
+			}
+
+			names := sink.Names()
+			if len(names) != len(tt.want) {
+				t.Fatalf("len(Names()) = %#v, want %#v", len(names), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got, _ := sink.Bytes(names[i]); string(got) != want {
+					t.Errorf("content of %#v = %#v, want %#v", names[i], string(got), want)
+				}
+			}
+		})
+	}
+}
+
+func TestByLines_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/empty"
+	prefix := "TestByLines_EmptyFile-"
+	outDir := t.TempDir()
+	nLines := 10
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	gerr := g.ByLines(nLines)
+	if gerr != nil {
+		t.Fatal("ByLines() failed:", gerr)
+	}
+
+	outFileName := prefix + "aa"
+	outFilePath := path.Join(outDir, outFileName)
+	_, err := os.Stat(outFilePath)
+	if err == nil {
+		t.Errorf("os.Stat(%#v) should be error", outFilePath)
+	}
+}
+
+func TestByLines_Directory(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata"
+	prefix := "TestByLines_Directory-"
+	outDir := t.TempDir()
+	nLines := 10
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByLines(nLines)
+	if !errors.Is(err, gosplit.ErrIsDirectory) {
+		t.Errorf("ByLines() with a directory should be error")
+	}
+}
+
+func TestByLines_InvalidNLines(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestByLines_InvalidNLines-"
+	outDir := t.TempDir()
+	nLines := 0
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByLines(nLines)
+	if !errors.Is(err, gosplit.ErrInvalidLines) {
+		t.Errorf("ByLines(%#v) should be error", nLines)
+	}
+}
+
+func TestByNumber(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestByNumber-"
+	outDir := t.TempDir()
+	nNumber := 4
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "aa", 363},
+		{prefix + "ab", 363},
+		{prefix + "ac", 363},
+		{prefix + "ad", 366},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByNumber(nNumber)
+	if err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestByNumber_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/empty"
+	prefix := "TestByNumber_EmptyFile-"
+	outDir := t.TempDir()
+	nNumber := 4
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "aa", 0},
+		{prefix + "ab", 0},
+		{prefix + "ac", 0},
+		{prefix + "ad", 0},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByNumber(nNumber)
+	if err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestByNumber_Directory(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata"
+	prefix := "TestByNumber_Directory-"
+	outDir := t.TempDir()
+	nNumber := 4
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByNumber(nNumber)
+	if !errors.Is(err, gosplit.ErrIsDirectory) {
+		t.Errorf("ByNumber() with a directory should be error")
+	}
+}
+
+func TestByNumber_Stdin(t *testing.T) {
+	t.Parallel()
+
+	filePath := "-"
+	prefix := "TestByNumber_Stdin-"
+	outDir := t.TempDir()
+	nNumber := 4
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByNumber(nNumber)
+	if !errors.Is(err, gosplit.ErrUnknownSize) {
+		t.Errorf("ByNumber() with STDIN should be error")
+	}
+}
+
+func TestByNumber_InvalidNBytes(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestByNumber_InvalidNBytes-"
+	outDir := t.TempDir()
+	nNumber := 0
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByNumber(nNumber)
+	if !errors.Is(err, gosplit.ErrInvalidNumber) {
+		t.Errorf("ByNumber(%#v) should be error", nNumber)
+	}
+}
+
+func TestExtractByNumber(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestExtractByNumber-"
+	nNumber := 4
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+
+	var offset int
+	for k, nBytes := range []int{363, 363, 363, 366} {
+		var b bytes.Buffer
+		g := helperNew(t, filePath, prefix, gosplit.Options{Output: &b})
+		gerr := g.ExtractByNumber(k+1, nNumber)
+		if gerr != nil {
+			t.Fatal("ExtractByNumber() failed:", gerr)
+		}
+
+		want := content[offset : offset+nBytes]
+		if got := b.Bytes(); !bytes.Equal(got, want) {
+			t.Errorf("ExtractByNumber(%#v, %#v) = %#v, want %#v", k+1, nNumber, string(got), string(want))
+		}
+		offset += nBytes
+	}
+}
+
+func TestExtractByNumber_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/empty"
+	prefix := "TestExtractByNumber_EmptyFile-"
+	k := 2
+	nNumber := 4
+
+	var b bytes.Buffer
+	g := helperNew(t, filePath, prefix, gosplit.Options{Output: &b})
+	err := g.ExtractByNumber(k, nNumber)
+	if err != nil {
+		t.Fatal("ExtractByNumber() failed:", err)
+	}
+
+	if b.Len() != 0 {
+		t.Errorf("ExtractByNumber(%#v, %#v) should output nothing, got %#v", k, nNumber, b.String())
+	}
+}
+
+func TestExtractByNumber_Stdin(t *testing.T) {
+	t.Parallel()
+
+	filePath := "-"
+	prefix := "TestExtractByNumber_Stdin-"
+	k := 1
+	nNumber := 4
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{Output: io.Discard})
+	err := g.ExtractByNumber(k, nNumber)
+	if !errors.Is(err, gosplit.ErrUnknownSize) {
+		t.Errorf("ExtractByNumber() with STDIN should be error")
+	}
+}
+
+func TestExtractByNumber_InvalidK(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestExtractByNumber_InvalidK-"
+	nNumber := 4
+
+	for _, k := range []int{0, 5} {
+		g := helperNew(t, filePath, prefix, gosplit.Options{Output: io.Discard})
+		err := g.ExtractByNumber(k, nNumber)
+		if !errors.Is(err, gosplit.ErrInvalidChunk) {
+			t.Errorf("ExtractByNumber(%#v, %#v) should be error", k, nNumber)
+		}
+	}
+}
+
+func TestByLineNumber(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestByLineNumber-"
+	outDir := t.TempDir()
+	nNumber := 4
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "aa", 387},
+		{prefix + "ab", 376},
+		{prefix + "ac", 326},
+		{prefix + "ad", 366},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByLineNumber(nNumber)
+	if err != nil {
+		t.Fatal("ByLineNumber() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestByLineNumber_LongLine(t *testing.T) {
+	t.Parallel()
+
+	prefix := "TestByLineNumber_LongLine-"
+	outDir := t.TempDir()
+	nNumber := 4
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "aa", 10},
+		{prefix + "ab", 0},
+		{prefix + "ac", 0},
+		{prefix + "ad", 2},
+	}
+
+	filePath := path.Join(t.TempDir(), "input")
+	if err := os.WriteFile(filePath, []byte("123456789\na\n"), 0o644); err != nil {
+		t.Fatal("failed to write:", err)
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByLineNumber(nNumber)
+	if err != nil {
+		t.Fatal("ByLineNumber() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestByLineNumber_Stdin(t *testing.T) {
+	t.Parallel()
+
+	filePath := "-"
+	prefix := "TestByLineNumber_Stdin-"
+	outDir := t.TempDir()
+	nNumber := 4
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByLineNumber(nNumber)
+	if !errors.Is(err, gosplit.ErrUnknownSize) {
+		t.Errorf("ByLineNumber() with STDIN should be error")
+	}
+}
+
+func TestExtractByLineNumber(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestExtractByLineNumber-"
+	nNumber := 4
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+
+	var offset int
+	for k, nBytes := range []int{387, 376, 326, 366} {
+		var b bytes.Buffer
+		g := helperNew(t, filePath, prefix, gosplit.Options{Output: &b})
+		gerr := g.ExtractByLineNumber(k+1, nNumber)
+		if gerr != nil {
+			t.Fatal("ExtractByLineNumber() failed:", gerr)
+		}
+
+		want := content[offset : offset+nBytes]
+		if got := b.Bytes(); !bytes.Equal(got, want) {
+			t.Errorf("ExtractByLineNumber(%#v, %#v) = %#v, want %#v", k+1, nNumber, string(got), string(want))
+		}
+		offset += nBytes
+	}
+}
+
+func TestByLineNumber_GNUBoundaries(t *testing.T) {
+	t.Parallel()
+
+	// the chunk sizes are taken from the output of GNU split -n l/N and l/K/N
+	cases := map[string]struct {
+		input   string
+		nNumber int
+		want    []int
+	}{
+		"remainder":     {"a\nb\nc\nd\ne\nf\ng\n", 3, []int{4, 4, 6}},
+		"uneven lines":  {"ab\ncd\nefgh\nij\n", 3, []int{6, 5, 3}},
+		"long line":     {"abc\nd\n", 5, []int{4, 0, 0, 0, 2}},
+		"no newline":    {"abcdefg", 3, []int{7, 0, 0}},
+		"fewer bytes":   {"a\nbb\n", 7, []int{2, 0, 3, 0, 0, 0, 0}},
+		"only newlines": {"\n\n\n", 5, []int{1, 1, 1, 0, 0}},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sink := gosplit.NewMemorySink()
+			g, gerr := gosplit.NewReaderAt(strings.NewReader(tt.input), int64(len(tt.input)), "x", gosplit.Options{Sink: sink})
+			if gerr != nil {
+				t.Fatal("NewReaderAt() failed:", gerr)
+			}
+			if err := g.ByLineNumber(tt.nNumber); err != nil {
+				t.Fatal("ByLineNumber() failed:", err)
+			}
+
+			names := sink.Names()
+			if len(names) != len(tt.want) {
+				t.Fatalf("len(Names()) = %#v, want %#v", len(names), len(tt.want))
+			}
+
+			var offset int
+			for k, nBytes := range tt.want {
+				want := tt.input[offset : offset+nBytes]
+				if got, _ := sink.Bytes(names[k]); string(got) != want {
+					t.Errorf("content of %#v = %#v, want %#v", names[k], string(got), want)
+				}
+
+				var b bytes.Buffer
+				g, gerr := gosplit.NewReaderAt(strings.NewReader(tt.input), int64(len(tt.input)), "x", gosplit.Options{Output: &b})
+				if gerr != nil {
+					t.Fatal("NewReaderAt() failed:", gerr)
+				}
+				if err := g.ExtractByLineNumber(k+1, tt.nNumber); err != nil {
+					t.Fatal("ExtractByLineNumber() failed:", err)
+				}
+				if got := b.String(); got != want {
+					t.Errorf("ExtractByLineNumber(%#v, %#v) = %#v, want %#v", k+1, tt.nNumber, got, want)
+				}
+				offset += nBytes
+			}
+		})
+	}
+}
+
+func TestByRoundRobin(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestByRoundRobin-"
+	outDir := t.TempDir()
+	nNumber := 4
+	outFiles := []struct {
+		name   string
+		nLines int
+	}{
+		{prefix + "aa", 11},
+		{prefix + "ab", 11},
+		{prefix + "ac", 10},
+		{prefix + "ad", 10},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByRoundRobin(nNumber)
+	if err != nil {
+		t.Fatal("ByRoundRobin() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountLines(t, outDir, outFile.name)
+		if result != outFile.nLines {
+			t.Errorf("helperCountLines(%#v) = %#v, want %#v", outFile.name, result, outFile.nLines)
+		}
+	}
+}
+
+func TestByRoundRobin_ElideEmptyFiles(t *testing.T) {
+	t.Parallel()
+
+	prefix := "TestByRoundRobin_ElideEmptyFiles-"
+	outDir := t.TempDir()
+	nNumber := 4
+
+	filePath := path.Join(t.TempDir(), "input")
+	if err := os.WriteFile(filePath, []byte("a\nb\n"), 0o644); err != nil {
+		t.Fatal("failed to write:", err)
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, ElideEmptyFiles: true})
+	gerr := g.ByRoundRobin(nNumber)
+	if gerr != nil {
+		t.Fatal("ByRoundRobin() failed:", gerr)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal("failed to read dir:", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("ByRoundRobin() created %#v files, want %#v", len(entries), 2)
+	}
+}
+
+func TestByRoundRobin_HugeNumber(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		opts    gosplit.Options
+		wantErr error
+		nFiles  int
+	}{
+		// the output files are created only for the lines
+		"elide": {gosplit.Options{ElideEmptyFiles: true}, nil, 42},
+		// the suffixes are checked before creating the output files
+		"suffix exhausted": {gosplit.Options{Suffix: gosplit.Suffix{Type: gosplit.SuffixNumeric, Length: 3}}, gosplit.ErrSuffixExhausted, 0},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sink := gosplit.NewMemorySink()
+			opts := tt.opts
+			opts.Sink = sink
+			g := helperNew(t, "testdata/example.txt", "x", opts)
+			if gerr := g.ByRoundRobin(math.MaxInt32); !errors.Is(gerr, tt.wantErr) {
+				t.Fatalf("ByRoundRobin() = %#v, want %#v", gerr, tt.wantErr)
+			}
+			if names := sink.Names(); len(names) != tt.nFiles {
+				t.Errorf("%d output files, want %d", len(names), tt.nFiles)
+			}
+		})
+	}
+}
+
+func TestExtractByRoundRobin(t *testing.T) {
+	t.Parallel()
+
+	prefix := "TestExtractByRoundRobin-"
+	k := 2
+	nNumber := 3
+	want := "b\ne\n"
+
+	filePath := path.Join(t.TempDir(), "input")
+	if err := os.WriteFile(filePath, []byte("a\nb\nc\nd\ne\nf\ng"), 0o644); err != nil {
+		t.Fatal("failed to write:", err)
+	}
+
+	var b bytes.Buffer
+	g := helperNew(t, filePath, prefix, gosplit.Options{Output: &b})
+	err := g.ExtractByRoundRobin(k, nNumber)
+	if err != nil {
+		t.Fatal("ExtractByRoundRobin() failed:", err)
+	}
+
+	got := b.String()
+	if got != want {
+		t.Errorf("ExtractByRoundRobin(%#v, %#v) = %#v, want %#v", k, nNumber, got, want)
+	}
+}
+
+func TestByBytes(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestByBytes-"
+	outDir := t.TempDir()
+	nBytes := int64(512)
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "aa", 512},
+		{prefix + "ab", 512},
+		{prefix + "ac", 431},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByBytes(nBytes)
+	if err != nil {
+		t.Fatal("ByBytes() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestByBytes_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/empty"
+	prefix := "TestByBytes_EmptyFile-"
+	outDir := t.TempDir()
+	nBytes := int64(512)
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	gerr := g.ByBytes(nBytes)
+	if gerr != nil {
+		t.Fatal("ByBytes() failed:", gerr)
+	}
+
+	outFileName := prefix + "aa"
+	outFilePath := path.Join(outDir, outFileName)
+	_, err := os.Stat(outFilePath)
+	if err == nil {
+		t.Errorf("os.Stat(%#v) should be error", outFilePath)
+	}
+}
+
+func TestByBytes_Directory(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata"
+	prefix := "TestByBytes_Directory-"
+	outDir := t.TempDir()
+	nBytes := int64(512)
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByBytes(nBytes)
+	if !errors.Is(err, gosplit.ErrIsDirectory) {
+		t.Errorf("ByBytes() with a directory should be error")
+	}
+}
+
+func TestByBytes_InvalidNBytes(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestByBytes_InvalidNBytes-"
+	outDir := t.TempDir()
+	nBytes := int64(0)
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByBytes(nBytes)
+	if !errors.Is(err, gosplit.ErrInvalidBytes) {
+		t.Errorf("ByBytes(%#v) should be error", nBytes)
+	}
+}
+
+func TestByLineBytes(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestByLineBytes-"
+	outDir := t.TempDir()
+	nBytes := int64(512)
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "aa", 457},
+		{prefix + "ab", 505},
+		{prefix + "ac", 493},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByLineBytes(nBytes)
+	if err != nil {
+		t.Fatal("ByLineBytes() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestByLineBytes_LongLine(t *testing.T) {
+	t.Parallel()
+
+	prefix := "TestByLineBytes_LongLine-"
+	outDir := t.TempDir()
+	nBytes := int64(3)
+	outFiles := []struct {
+		name    string
+		content string
+	}{
+		{prefix + "aa", "aaa"},
+		{prefix + "ab", "a\n"},
+		{prefix + "ac", "b\nc"},
+	}
+
+	filePath := path.Join(t.TempDir(), "input")
+	if err := os.WriteFile(filePath, []byte("aaaa\nb\nc"), 0o644); err != nil {
+		t.Fatal("failed to write:", err)
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	gerr := g.ByLineBytes(nBytes)
+	if gerr != nil {
+		t.Fatal("ByLineBytes() failed:", gerr)
+	}
+
+	for _, outFile := range outFiles {
+		b, err := os.ReadFile(path.Join(outDir, outFile.name))
+		if err != nil {
+			t.Fatal("failed to read:", err)
+		}
+		if got := string(b); got != outFile.content {
+			t.Errorf("content of %#v = %#v, want %#v", outFile.name, got, outFile.content)
+		}
+	}
+}
+
+func TestByLineBytes_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/empty"
+	prefix := "TestByLineBytes_EmptyFile-"
+	outDir := t.TempDir()
+	nBytes := int64(512)
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	gerr := g.ByLineBytes(nBytes)
+	if gerr != nil {
+		t.Fatal("ByLineBytes() failed:", gerr)
+	}
+
+	outFileName := prefix + "aa"
+	outFilePath := path.Join(outDir, outFileName)
+	_, err := os.Stat(outFilePath)
+	if err == nil {
+		t.Errorf("os.Stat(%#v) should be error", outFilePath)
+	}
+}
+
+func TestByLineBytes_InvalidNBytes(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestByLineBytes_InvalidNBytes-"
+	outDir := t.TempDir()
+	nBytes := int64(0)
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir})
+	err := g.ByLineBytes(nBytes)
+	if !errors.Is(err, gosplit.ErrInvalidBytes) {
+		t.Errorf("ByLineBytes(%#v) should be error", nBytes)
+	}
+}
+
+func TestOptions_Verbose(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestOptions_Verbose-"
+	outDir := t.TempDir()
+	nNumber := 4
+
+	path := path.Join(outDir, prefix)
+	want := "creating file \"" + path + "aa\"\n" +
+		"creating file \"" + path + "ab\"\n" +
+		"creating file \"" + path + "ac\"\n" +
+		"creating file \"" + path + "ad\"\n"
+
+	var b bytes.Buffer
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Verbose: &b})
+	err := g.ByNumber(nNumber)
+	if err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+
+	got := b.String()
+	if got != want {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestOptions_NumericSuffix(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestOptions_NumericSuffix-"
+	outDir := t.TempDir()
+	nNumber := 4
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "00", 363},
+		{prefix + "01", 363},
+		{prefix + "02", 363},
+		{prefix + "03", 366},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Suffix: gosplit.Suffix{Type: gosplit.SuffixNumeric}})
+	err := g.ByNumber(nNumber)
+	if err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestOptions_Suffix(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestOptions_Suffix-"
+	outDir := t.TempDir()
+	nNumber := 4
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "09.txt", 363},
+		{prefix + "0a.txt", 363},
+		{prefix + "0b.txt", 363},
+		{prefix + "0c.txt", 366},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Suffix: gosplit.Suffix{Type: gosplit.SuffixHex, Start: 9, Additional: ".txt"}})
+	err := g.ByNumber(nNumber)
+	if err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestOptions_SuffixLength(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestOptions_SuffixLength-"
+	outDir := t.TempDir()
+	nNumber := 4
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "aaa", 363},
+		{prefix + "aab", 363},
+		{prefix + "aac", 363},
+		{prefix + "aad", 366},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Suffix: gosplit.Suffix{Length: 3}})
+	err := g.ByNumber(nNumber)
+	if err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestOptions_SuffixLength_Exhausted(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestOptions_SuffixLength_Exhausted-"
+	outDir := t.TempDir()
+	nLines := 1
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Suffix: gosplit.Suffix{Type: gosplit.SuffixNumeric, Length: 1}})
+	err := g.ByLines(nLines)
+	if !errors.Is(err, gosplit.ErrSuffixExhausted) {
+		t.Errorf("ByLines() = %#v, want %#v", err, gosplit.ErrSuffixExhausted)
+	}
+}
+
+func TestSuffixWidening(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		suffixType gosplit.SuffixType
+		nLines     int
+		outFiles   []string
+	}{
+		"alphabetic": {gosplit.SuffixAlphabetic, 700, []string{"aa", "yz", "zaaa", "zabx"}},
+		"numeric":    {gosplit.SuffixNumeric, 100, []string{"00", "89", "9000", "9009"}},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			prefix := "TestSuffixWidening-"
+			outDir := t.TempDir()
+
+			var b bytes.Buffer
+			for i := 0; i < tt.nLines; i++ {
+				fmt.Fprintln(&b, i)
+			}
+			filePath := path.Join(t.TempDir(), "input")
+			if err := os.WriteFile(filePath, b.Bytes(), 0o644); err != nil {
+				t.Fatal("failed to write:", err)
+			}
+
+			g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Suffix: gosplit.Suffix{Type: tt.suffixType}})
+			gerr := g.ByLines(1)
+			if gerr != nil {
+				t.Fatal("ByLines() failed:", gerr)
+			}
+
+			for _, suffix := range tt.outFiles {
+				result := helperCountLines(t, outDir, prefix+suffix)
+				if result != 1 {
+					t.Errorf("helperCountLines(%#v) = %#v, want %#v", prefix+suffix, result, 1)
+				}
+			}
+
+			entries, err := os.ReadDir(outDir)
+			if err != nil {
+				t.Fatal("failed to read dir:", err)
+			}
+			if len(entries) != tt.nLines {
+				t.Errorf("ByLines() created %#v files, want %#v", len(entries), tt.nLines)
+			}
+		})
+	}
+}
+
+func TestOptions_Filter(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the filter command is written for sh")
+	}
+
+	filePath := "testdata/example.txt"
+	prefix := "TestOptions_Filter-"
+	outDir := t.TempDir()
+	nBytes := int64(512)
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "aa.txt", 512},
+		{prefix + "ab.txt", 512},
+		{prefix + "ac.txt", 431},
+	}
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Filter: `cat > "$FILE.txt"`})
+	err := g.ByBytes(nBytes)
+	if err != nil {
+		t.Fatal("ByBytes() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestOptions_Filter_BrokenPipe(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the filter command is written for sh")
+	}
+
+	filePath := "testdata/example.txt"
+	prefix := "TestOptions_Filter_BrokenPipe-"
+	outDir := t.TempDir()
+	nLines := 10
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Filter: "exec 0<&-"})
+	err := g.ByLines(nLines)
+	if err != nil {
+		t.Errorf("ByLines() with the filter exiting early should not be error: %v", err)
+	}
+}
+
+func TestOptions_Filter_KilledByBrokenPipe(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("there is no SIGPIPE on Windows")
+	}
+
+	filePath := "testdata/example.txt"
+	prefix := "TestOptions_Filter_KilledByBrokenPipe-"
+	outDir := t.TempDir()
+	nLines := 10
+
+	// the same as "head -1" killed by SIGPIPE when writing to a closed pipe
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Filter: "kill -PIPE $$"})
+	err := g.ByLines(nLines)
+	if err != nil {
+		t.Errorf("ByLines() with the filter killed by SIGPIPE should not be error: %v", err)
+	}
+}
+
+func TestOptions_Filter_Failed(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the filter command is written for sh")
+	}
+
+	filePath := "testdata/example.txt"
+	prefix := "TestOptions_Filter_Failed-"
+	outDir := t.TempDir()
+	nLines := 10
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Filter: "cat > /dev/null; exit 3"})
+	err := g.ByLines(nLines)
+	if !errors.Is(err, gosplit.ErrFilterFailed) {
+		t.Errorf("ByLines() = %#v, want %#v", err, gosplit.ErrFilterFailed)
+	}
+}
+
+func TestOptions_Separator(t *testing.T) {
+	t.Parallel()
+
+	input := "a\x00bb\x00ccc\x00d\n\x00"
+	cases := map[string]struct {
+		split    func(g *gosplit.GoSplit) error
+		outFiles []string
+	}{
+		"ByLines": {
+			func(g *gosplit.GoSplit) error { return g.ByLines(2) },
+			[]string{"a\x00bb\x00", "ccc\x00d\n\x00"},
+		},
+		"ByLineNumber": {
+			func(g *gosplit.GoSplit) error { return g.ByLineNumber(2) },
+			[]string{"a\x00bb\x00ccc\x00", "d\n\x00"},
+		},
+		"ByRoundRobin": {
+			func(g *gosplit.GoSplit) error { return g.ByRoundRobin(2) },
+			[]string{"a\x00ccc\x00", "bb\x00d\n\x00"},
+		},
+		"ByLineBytes": {
+			func(g *gosplit.GoSplit) error { return g.ByLineBytes(6) },
+			[]string{"a\x00bb\x00", "ccc\x00", "d\n\x00"},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			prefix := "TestOptions_Separator-"
+			outDir := t.TempDir()
+
+			filePath := path.Join(t.TempDir(), "input")
+			if err := os.WriteFile(filePath, []byte(input), 0o644); err != nil {
+				t.Fatal("failed to write:", err)
+			}
+
+			g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, Separator: "\x00", Suffix: gosplit.Suffix{Length: 1}})
+			if err := tt.split(g); err != nil {
+				t.Fatal("split failed:", err)
+			}
+
+			for i, want := range tt.outFiles {
+				outFileName := prefix + string(rune('a'+i))
+				b, err := os.ReadFile(path.Join(outDir, outFileName))
+				if err != nil {
+					t.Fatal("failed to read:", err)
+				}
+				if got := string(b); got != want {
+					t.Errorf("content of %#v = %#v, want %#v", outFileName, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOptions_ElideEmptyFiles(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/empty"
+	prefix := "TestOptions_ElideEmptyFiles-"
+	outDir := t.TempDir()
+	nNumber := 4
+
+	g := helperNew(t, filePath, prefix, gosplit.Options{OutDir: outDir, ElideEmptyFiles: true})
+	gerr := g.ByNumber(nNumber)
+	if gerr != nil {
+		t.Fatal("ByNumber() failed:", gerr)
+	}
+
+	outFileName := prefix + "aa"
+	outFilePath := path.Join(outDir, outFileName)
+	_, err := os.Stat(outFilePath)
+	if err == nil {
+		t.Errorf("os.Stat(%#v) should be error", outFilePath)
+	}
+}
+
+func TestOptions_ElideEmptyFiles_HugeNumber(t *testing.T) {
+	t.Parallel()
+
+	// the chunks are not held at once, and the empty ones at the end are skipped
+	nNumber := math.MaxInt32
+	cases := map[string]struct {
+		split  func(g *gosplit.GoSplit) error
+		jobs   int
+		nFiles int
+	}{
+		"ByNumber":          {func(g *gosplit.GoSplit) error { return g.ByNumber(nNumber) }, 0, 1455},
+		"ByNumber jobs":     {func(g *gosplit.GoSplit) error { return g.ByNumber(nNumber) }, 4, 1455},
+		"ByLineNumber":      {func(g *gosplit.GoSplit) error { return g.ByLineNumber(nNumber) }, 0, 42},
+		"ByLineNumber jobs": {func(g *gosplit.GoSplit) error { return g.ByLineNumber(nNumber) }, 4, 42},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sink := gosplit.NewMemorySink()
+			g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{Sink: sink, ElideEmptyFiles: true, Jobs: tt.jobs})
+			if err := tt.split(g); err != nil {
+				t.Fatal("split failed:", err)
+			}
+			if names := sink.Names(); len(names) != tt.nFiles {
+				t.Errorf("%d output files, want %d", len(names), tt.nFiles)
+			}
+		})
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	t.Parallel()
+
+	prefix := "TestNewReader-"
+	outDir := t.TempDir()
+	nLines := 2
+	outFiles := []struct {
+		name    string
+		content string
+	}{
+		{prefix + "aa", "a\nb\n"},
+		{prefix + "ab", "c\n"},
+	}
+
+	g, err := gosplit.NewReader(strings.NewReader("a\nb\nc\n"), prefix, gosplit.Options{OutDir: outDir})
+	if err != nil {
+		t.Fatal("NewReader() failed:", err)
+	}
+	if err := g.ByLines(nLines); err != nil {
+		t.Fatal("ByLines() failed:", err)
+	}
+
+	for _, outFile := range outFiles {
+		b, err := os.ReadFile(path.Join(outDir, outFile.name))
+		if err != nil {
+			t.Fatal("failed to read:", err)
+		}
+		if got := string(b); got != outFile.content {
+			t.Errorf("content of %#v = %#v, want %#v", outFile.name, got, outFile.content)
+		}
+	}
+}
+
+func TestNewReader_UnknownSize(t *testing.T) {
+	t.Parallel()
+
+	prefix := "TestNewReader_UnknownSize-"
+	outDir := t.TempDir()
+	nNumber := 4
+
+	g, gerr := gosplit.NewReader(strings.NewReader("a\nb\nc\n"), prefix, gosplit.Options{OutDir: outDir})
+	if gerr != nil {
+		t.Fatal("NewReader() failed:", gerr)
+	}
+	err := g.ByNumber(nNumber)
+	if !errors.Is(err, gosplit.ErrUnknownSize) {
+		t.Errorf("ByNumber() = %#v, want %#v", err, gosplit.ErrUnknownSize)
+	}
+}
+
+func TestNewReader_File(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestNewReader_File-"
+	outDir := t.TempDir()
+	nNumber := 2
+	outFiles := []struct {
+		name   string
+		nBytes int64
+	}{
+		{prefix + "aa", 677},
+		{prefix + "ab", 678},
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		t.Fatal("failed to open:", err)
+	}
+	defer f.Close()
+
+	// the size is counted from the current offset
+	if _, err := f.Seek(100, io.SeekStart); err != nil {
+		t.Fatal("failed to seek:", err)
+	}
+
+	g, gerr := gosplit.NewReader(f, prefix, gosplit.Options{OutDir: outDir})
+	if gerr != nil {
+		t.Fatal("NewReader() failed:", gerr)
+	}
+	if gerr := g.ByNumber(nNumber); gerr != nil {
+		t.Fatal("ByNumber() failed:", gerr)
+	}
+
+	for _, outFile := range outFiles {
+		result := helperCountBytes(t, outDir, outFile.name)
+		if result != outFile.nBytes {
+			t.Errorf("helperCountBytes(%#v) = %#v, want %#v", outFile.name, result, outFile.nBytes)
+		}
+	}
+}
+
+func TestNewReaderAt(t *testing.T) {
+	t.Parallel()
+
+	prefix := "TestNewReaderAt-"
+	k := 2
+	nNumber := 3
+	want := "b\n"
+
+	content := "a\nb\nc\n"
+	var b bytes.Buffer
+	g, err := gosplit.NewReaderAt(strings.NewReader(content), int64(len(content)), prefix, gosplit.Options{Output: &b})
+	if err != nil {
+		t.Fatal("NewReaderAt() failed:", err)
+	}
+	if err := g.ExtractByLineNumber(k, nNumber); err != nil {
+		t.Fatal("ExtractByLineNumber() failed:", err)
+	}
+
+	if got := b.String(); got != want {
+		t.Errorf("ExtractByLineNumber(%#v, %#v) = %#v, want %#v", k, nNumber, got, want)
+	}
+}
+
+func TestNewReaderAt_InvalidSize(t *testing.T) {
+	t.Parallel()
+
+	prefix := "TestNewReaderAt_InvalidSize-"
+
+	_, err := gosplit.NewReaderAt(strings.NewReader(""), -1, prefix, gosplit.Options{})
+	if !errors.Is(err, gosplit.ErrInvalidBytes) {
+		t.Errorf("NewReaderAt() = %#v, want %#v", err, gosplit.ErrInvalidBytes)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		in        string
+		want      int64
+		expectErr bool
+	}{
+		"2":    {"2", 2, false},
+		"2b":   {"2b", 2 * 512, false},
+		"2K":   {"2K", 2 * 1024, false},
+		"2KiB": {"2KiB", 2 * 1024, false},
+		"2KB":  {"2KB", 2 * 1000, false},
+		"7E":   {"7E", 7 * 1024 * 1024 * 1024 * 1024 * 1024 * 1024, false},
+		"9EB":  {"9EB", 9 * 1000 * 1000 * 1000 * 1000 * 1000 * 1000, false},
+		"8E":   {"8E", 0, true},
+		"10EB": {"10EB", 0, true},
+		"1Z":   {"1Z", 0, true},
+		"1ZB":  {"1ZB", 0, true},
+		"0":    {"0", 0, true},
+		"0K":   {"0K", 0, true},
+		"1.5":  {"1.5", 0, true},
+		"-1":   {"-1", 0, true},
+		"2iB":  {"2iB", 0, true},
+		"2B":   {"2B", 0, true},
+		"2biB": {"2biB", 0, true},
+		"2bB":  {"2bB", 0, true},
+		"X":    {"X", 0, true},
+		"2X":   {"2X", 0, true},
+		"2KX":  {"2KX", 0, true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := gosplit.ParseSize(tt.in)
+			if tt.expectErr && err == nil {
+				t.Fatal("want err")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			if tt.want != got {
+				t.Errorf("ParseSize(%#v) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChunks(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		in        string
+		wantMode  gosplit.ChunkMode
+		wantK     int
+		wantN     int
+		expectErr bool
+	}{
+		"4":     {"4", gosplit.ChunkBytes, 0, 4, false},
+		"1/4":   {"1/4", gosplit.ChunkBytes, 1, 4, false},
+		"4/4":   {"4/4", gosplit.ChunkBytes, 4, 4, false},
+		"l/4":   {"l/4", gosplit.ChunkLines, 0, 4, false},
+		"l/1/4": {"l/1/4", gosplit.ChunkLines, 1, 4, false},
+		"r/4":   {"r/4", gosplit.ChunkRoundRobin, 0, 4, false},
+		"r/1/4": {"r/1/4", gosplit.ChunkRoundRobin, 1, 4, false},
+		"0":     {"0", 0, 0, 0, true},
+		"-1":    {"-1", 0, 0, 0, true},
+		"0/4":   {"0/4", 0, 0, 0, true},
+		"5/4":   {"5/4", 0, 0, 0, true},
+		"1/0":   {"1/0", 0, 0, 0, true},
+		"1/":    {"1/", 0, 0, 0, true},
+		"/4":    {"/4", 0, 0, 0, true},
+		"l/0":   {"l/0", 0, 0, 0, true},
+		"l/5/4": {"l/5/4", 0, 0, 0, true},
+		"x/4":   {"x/4", 0, 0, 0, true},
+		"X":     {"X", 0, 0, 0, true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			gotMode, gotK, gotN, err := gosplit.ParseChunks(tt.in)
+			if tt.expectErr && err == nil {
+				t.Fatal("want err")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			if tt.wantMode != gotMode || tt.wantK != gotK || tt.wantN != gotN {
+				t.Errorf("ParseChunks(%#v) = %#v, %#v, %#v, want %#v, %#v, %#v", tt.in, gotMode, gotK, gotN, tt.wantMode, tt.wantK, tt.wantN)
+			}
+		})
+	}
+}
+
+func TestParseSeparator(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		in        string
+		want      string
+		expectErr bool
+	}{
+		"comma":   {",", ",", false},
+		"tab":     {"\t", "\t", false},
+		"NUL":     {`\0`, "\x00", false},
+		"empty":   {"", "", true},
+		"multi":   {",,", "", true},
+		"unicode": {"\u3042", "", true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := gosplit.ParseSeparator(tt.in)
+			if tt.expectErr && err == nil {
+				t.Fatal("want err")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			if tt.want != got {
+				t.Errorf("ParseSeparator(%#v) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+//go:build !windows
+
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// getDiskFreeSpace returns free disk space where dirPath exists.
+func getDiskFreeSpace(dirPath string) (uint64, g.Error) {
+	var stat unix.Statfs_t
+
+	if err := unix.Statfs(dirPath, &stat); err != nil {
+		return 0, wrapper.Errorf("faied to unix.Statfs: %w", err)
+	}
+	freeBytesAvailable := stat.Bavail * uint64(stat.Bsize)
+	return freeBytesAvailable, nil
+}
+
+// getFileID returns the inode number of f.
+func getFileID(f *os.File) (uint64, g.Error) {
+	var stat unix.Stat_t
+
+	if err := unix.Fstat(int(f.Fd()), &stat); err != nil {
+		return 0, wrapper.Errorf("failed to unix.Fstat: %w", err)
+	}
+	return uint64(stat.Ino), nil
+}
+
+// shellCommand returns exec.Cmd to run command with the shell, $SHELL or /bin/sh.
+func shellCommand(command string) *exec.Cmd {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	return exec.Command(shell, "-c", command)
+}
+
+// isBrokenPipe reports whether err is EPIPE.
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, unix.EPIPE)
+}
+
+// isKilledByBrokenPipe reports whether err is the exit of the command killed by SIGPIPE.
+func isKilledByBrokenPipe(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && status.Signal() == syscall.SIGPIPE
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+// getDiskFreeSpace returns free disk space where dirPath exists.
+func getDiskFreeSpace(dirPath string) (uint64, g.Error) {
+	var (
+		freeBytesAvailableToCaller uint64
+		totalNumberOfBytes         uint64
+		totalNumberOfFreeBytes     uint64
+	)
+
+	dirPath = filepath.FromSlash(dirPath)
+	err := windows.GetDiskFreeSpaceEx(windows.StringToUTF16Ptr(dirPath), &freeBytesAvailableToCaller, &totalNumberOfBytes, &totalNumberOfFreeBytes)
+	if err != nil {
+		return 0, wrapper.Errorf("failed to windows.GetFreeSpaceEx: %w", err)
+	}
+	return freeBytesAvailableToCaller, nil
+}
+
+// getFileID returns the file index of f.
+func getFileID(f *os.File) (uint64, g.Error) {
+	var info windows.ByHandleFileInformation
+
+	if err := windows.GetFileInformationByHandle(windows.Handle(f.Fd()), &info); err != nil {
+		return 0, wrapper.Errorf("failed to windows.GetFileInformationByHandle: %w", err)
+	}
+	return uint64(info.FileIndexHigh)<<32 | uint64(info.FileIndexLow), nil
+}
+
+// shellCommand returns exec.Cmd to run command with the shell, %ComSpec% or cmd.exe.
+func shellCommand(command string) *exec.Cmd {
+	shell := os.Getenv("ComSpec")
+	if shell == "" {
+		shell = "cmd.exe"
+	}
+	return exec.Command(shell, "/C", command)
+}
+
+// isBrokenPipe reports whether err is ERROR_BROKEN_PIPE or ERROR_NO_DATA.
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, windows.ERROR_BROKEN_PIPE) || errors.Is(err, windows.ERROR_NO_DATA)
+}
+
+// isKilledByBrokenPipe reports whether err is the exit of the command killed by SIGPIPE.
+//
+// always false because there is no SIGPIPE on Windows.
+func isKilledByBrokenPipe(err error) bool {
+	return false
+}
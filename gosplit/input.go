@@ -0,0 +1,128 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"io"
+	"os"
+)
+
+// input represents the opened input of GoSplit.
+type input struct {
+	r     io.Reader
+	rs    io.ReadSeeker // nil if the size is unknown
+	size  int64         // -1 if unknown
+	close func() error
+	f     *os.File // the regular file of the input, or nil
+
+	// bDecompressed tells whether the input is decompressed, whose size is unknown
+	bDecompressed bool
+}
+
+// Close closes the input if it is opened by GoSplit.
+func (in *input) Close() error {
+	if in.close == nil {
+		return nil
+	}
+	return in.close()
+}
+
+// openInput opens the input, whose size may be unknown, decompressing it if enabled.
+func (g *GoSplit) openInput() (*input, g.Error) {
+	in, gerr := g.openRawInput()
+	if gerr != nil || !g.bDecompress {
+		return in, gerr
+	}
+
+	decompressed, gerr := g.decompressInput(in)
+	if gerr != nil {
+		in.Close()
+		return nil, gerr
+	}
+	return decompressed, nil
+}
+
+// openRawInput opens the input as it is.
+func (g *GoSplit) openRawInput() (*input, g.Error) {
+	switch {
+	case g.ra != nil:
+		sr := io.NewSectionReader(g.ra, 0, g.raSize)
+		return &input{r: sr, rs: sr, size: g.raSize}, nil
+	case g.r != nil:
+		return newInput(g.r)
+	case g.filePath == "-":
+		return newInput(os.Stdin)
+	}
+
+	f, err := os.Open(g.filePath)
+	if err != nil {
+		return nil, wrapper.Errorf("failed to open: %w", err)
+	}
+
+	in, gerr := newInput(f)
+	if gerr != nil {
+		f.Close()
+		return nil, gerr
+	}
+	in.close = f.Close
+	return in, nil
+}
+
+// openSizedInput opens the input with checking that its size is known in advance.
+//
+// The size of a stream is given by the hint, which is used only if bSeek is false.
+// Otherwise, the stream is spooled into a temporary file if it is decompressed or spooling is enabled.
+func (g *GoSplit) openSizedInput(bSeek bool) (*input, g.Error) {
+	in, gerr := g.openInput()
+	if gerr != nil {
+		return nil, gerr
+	}
+	g.hintSize(in)
+
+	switch {
+	case in.rs != nil:
+		return in, nil
+	case in.size >= 0 && !bSeek:
+		return in, nil
+	case in.bDecompressed || g.bSpool:
+		defer in.Close()
+		return g.spoolInput(in)
+	}
+
+	in.Close()
+	return nil, wrapper.Errorf("%w", ErrUnknownSize)
+}
+
+// newInput returns the input of r. Its size is known only when r is a regular file.
+func newInput(r io.Reader) (*input, g.Error) {
+	in := &input{r: r, size: -1}
+
+	f, ok := r.(*os.File)
+	if !ok {
+		return in, nil
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, wrapper.Errorf("failed to stat: %w", err)
+	}
+	if fi.IsDir() {
+		return nil, wrapper.Errorf("%w", ErrIsDirectory)
+	}
+	if !fi.Mode().IsRegular() {
+		return in, nil
+	}
+
+	// the file may have been read partially, e.g. standard input
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return in, nil
+	}
+
+	sr := io.NewSectionReader(f, offset, max(fi.Size()-offset, 0))
+	in.r = sr
+	in.rs = sr
+	in.size = sr.Size()
+	in.f = f
+	return in, nil
+}
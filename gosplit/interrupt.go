@@ -0,0 +1,103 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Interrupt stops the running split of g and the later ones at a safe point, i.e. before the next write.
+//
+// The split closes the output file being written and fails with ErrInterrupted, which reports the number of
+// the output files completed. The output files created by the split are removed unless KeepPartial or Resume.
+// It is safe to call from another goroutine, e.g. a signal handler.
+func (g *GoSplit) Interrupt() {
+	g.interrupt()
+}
+
+// checkInterrupted returns ErrInterrupted if the split has been interrupted,
+// or the error of the context of the split if it is done.
+func (g *GoSplit) checkInterrupted() g.Error {
+	if g.ctxInterrupt.Err() != nil {
+		return wrapper.Errorf("%w", ErrInterrupted)
+	}
+	if g.ctx != nil && g.ctx.Err() != nil {
+		return wrapper.Errorf("%w", g.ctx.Err())
+	}
+	return nil
+}
+
+// isInterrupted returns true if err is caused by Interrupt or the context of the split.
+func isInterrupted(err error) bool {
+	return errors.Is(err, ErrInterrupted) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// interruptWriter fails writing to an output file after the split is interrupted,
+// counting the output files completed without interruption.
+type interruptWriter struct {
+	io.WriteCloser
+	g              *GoSplit
+	errInterrupted g.Error // the error of the write failed by the interruption, or nil
+}
+
+// interruptOutFile returns the writer of the output file w which stops writing when the split is interrupted.
+func (g *GoSplit) interruptOutFile(w io.WriteCloser) io.WriteCloser {
+	return &interruptWriter{WriteCloser: w, g: g}
+}
+
+// Write implements io.Writer.
+func (iw *interruptWriter) Write(p []byte) (int, error) {
+	if gerr := iw.g.checkInterrupted(); gerr != nil {
+		iw.errInterrupted = gerr
+		return 0, gerr
+	}
+	return iw.WriteCloser.Write(p)
+}
+
+// Close implements io.Closer, discarding the output file instead if a write has been interrupted,
+// since the error may be swallowed by the writers above, e.g. the compressor.
+func (iw *interruptWriter) Close() error {
+	if iw.errInterrupted != nil {
+		iw.Abort()
+		return iw.errInterrupted
+	}
+	if err := iw.WriteCloser.Close(); err != nil {
+		return err
+	}
+	iw.g.nCompleted.Add(1)
+	return nil
+}
+
+// Abort implements aborter, counting the output file discarded from the sink.
+func (iw *interruptWriter) Abort() error {
+	if _, ok := iw.WriteCloser.(aborter); ok {
+		iw.g.nDiscarded.Add(1)
+	}
+	return abortOutFile(iw.WriteCloser)
+}
+
+// interruptOutput returns the writer of w which stops writing when the extraction is interrupted.
+func (g *GoSplit) interruptOutput(w io.Writer) io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		if gerr := g.checkInterrupted(); gerr != nil {
+			return 0, gerr
+		}
+		return w.Write(p)
+	})
+}
+
+// interruptedError returns the error of the interruption cause reporting nCompleted of nKept output files,
+// which are removed if bRemoved.
+func interruptedError(cause error, nCompleted int, nKept int, bRemoved bool) g.Error {
+	note := ""
+	switch nPartial := nKept - nCompleted; {
+	case bRemoved:
+		note = ", all removed"
+	case nPartial > 0:
+		note = fmt.Sprintf(", %d partial kept", nPartial)
+	}
+	return wrapper.Errorf("%w: %d output files completed%s", cause, nCompleted, note)
+}
@@ -0,0 +1,157 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// interruptingWriter interrupts the split when the verbose output contains trigger.
+type interruptingWriter struct {
+	g       *gosplit.GoSplit
+	trigger string
+}
+
+func (w *interruptingWriter) Write(p []byte) (int, error) {
+	if strings.Contains(string(p), w.trigger) {
+		w.g.Interrupt()
+	}
+	return len(p), nil
+}
+
+func TestGoSplit_Interrupt(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		bKeepPartial bool
+		wantErr      string
+		wantFiles    int
+	}{
+		"remove": {false, "interrupted: 2 output files completed, all removed", 0},
+		"keep":   {true, "interrupted: 2 output files completed", 2},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			outDir := t.TempDir()
+			verbose := &interruptingWriter{trigger: "xac"}
+			opts := gosplit.Options{OutDir: outDir, KeepPartial: tt.bKeepPartial, Verbose: verbose}
+			g := helperNew(t, "testdata/example.txt", "x", opts)
+			verbose.g = g
+
+			gerr := g.ByLines(10)
+			if !errors.Is(gerr, gosplit.ErrInterrupted) {
+				t.Fatalf("ByLines() = %#v, want %#v", gerr, gosplit.ErrInterrupted)
+			}
+			if !strings.HasSuffix(gerr.Error(), tt.wantErr) {
+				t.Errorf("ByLines() = %#v, want %#v", gerr.Error(), tt.wantErr)
+			}
+
+			entries, err := os.ReadDir(outDir)
+			if err != nil {
+				t.Fatal("failed to read dir:", err)
+			}
+			if len(entries) != tt.wantFiles {
+				t.Errorf("%d files left in the output directory, want %d", len(entries), tt.wantFiles)
+			}
+		})
+	}
+}
+
+func TestGoSplit_Interrupt_BeforeSplit(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		split func(g *gosplit.GoSplit) error
+	}{
+		"ByBytes":             {func(g *gosplit.GoSplit) error { return g.ByBytes(100) }},
+		"ByNumber jobs":       {func(g *gosplit.GoSplit) error { return g.ByNumber(4) }},
+		"ByRoundRobin":        {func(g *gosplit.GoSplit) error { return g.ByRoundRobin(4) }},
+		"ExtractByNumber":     {func(g *gosplit.GoSplit) error { return g.ExtractByNumber(1, 4) }},
+		"ExtractByRoundRobin": {func(g *gosplit.GoSplit) error { return g.ExtractByRoundRobin(1, 4) }},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sink := gosplit.NewMemorySink()
+			g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{Sink: sink, Jobs: 2, Output: io.Discard})
+			g.Interrupt()
+
+			err := tt.split(g)
+			if !errors.Is(err, gosplit.ErrInterrupted) {
+				t.Fatalf("split = %#v, want %#v", err, gosplit.ErrInterrupted)
+			}
+			if names := sink.Names(); len(names) != 0 {
+				t.Errorf("Names() = %#v, want empty", names)
+			}
+		})
+	}
+}
+
+// interruptingSink interrupts the split after the first write to the output file of trigger.
+type interruptingSink struct {
+	gosplit.Sink
+	g       *gosplit.GoSplit
+	trigger string
+}
+
+func (s *interruptingSink) Create(name string) (io.WriteCloser, error) {
+	w, err := s.Sink.Create(name)
+	if err != nil || name != s.trigger {
+		return w, err
+	}
+	return &interruptingOutFile{WriteCloser: w, g: s.g}, nil
+}
+
+// interruptingOutFile interrupts the split after the first write.
+type interruptingOutFile struct {
+	io.WriteCloser
+	g *gosplit.GoSplit
+}
+
+func (w *interruptingOutFile) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	w.g.Interrupt()
+	return n, err
+}
+
+func (w *interruptingOutFile) Abort() error {
+	return w.WriteCloser.(interface{ Abort() error }).Abort()
+}
+
+func TestGoSplit_Interrupt_Compressed(t *testing.T) {
+	t.Parallel()
+
+	// the compressor buffers the writes after the interruption until it is closed
+	outDir := t.TempDir()
+	sink := &interruptingSink{Sink: gosplit.NewDirSink(outDir), trigger: "xac.gz"}
+	opts := gosplit.Options{Sink: sink, Compress: gosplit.CompressGzip, KeepPartial: true}
+	g := helperNew(t, "testdata/example.txt", "x", opts)
+	sink.g = g
+
+	gerr := g.ByLines(10)
+	if !errors.Is(gerr, gosplit.ErrInterrupted) {
+		t.Fatalf("ByLines() = %#v, want %#v", gerr, gosplit.ErrInterrupted)
+	}
+	if want := "interrupted: 2 output files completed"; !strings.HasSuffix(gerr.Error(), want) {
+		t.Errorf("ByLines() = %#v, want %#v", gerr.Error(), want)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal("failed to read dir:", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("%d files left in the output directory, want 2", len(entries))
+	}
+}
@@ -0,0 +1,135 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"context"
+	"io"
+	"sync"
+)
+
+// concurrentReaderAt returns io.ReaderAt of r to write output files concurrently, if it is enabled and possible.
+//
+// The output files are written sequentially when the size is unknown, r cannot be read at offsets,
+// or the split depends on the order of reading, i.e. with Manifest, the filter command or the journal.
+func (g *GoSplit) concurrentReaderAt(r io.Reader, fileSize int64) (io.ReaderAt, bool) {
+	if g.jobs <= 1 || fileSize < 0 || g.wManifest != nil || g.filterCommand != "" || g.journal != nil {
+		return nil, false
+	}
+
+	ra, ok := r.(io.ReaderAt)
+	return ra, ok
+}
+
+// chunkJob is an output file to be written with a byte range of the input.
+type chunkJob struct {
+	w  io.WriteCloser
+	sr *io.SectionReader
+}
+
+// doByBoundariesAt splits the content from io.ReaderAt into files at the end offsets of ends,
+// writing them concurrently by g.jobs workers.
+//
+// The output files are created in order, so that their names are the same as doByBoundaries.
+// The first error cancels the rest of the output files.
+func (g *GoSplit) doByBoundariesAt(ra io.ReaderAt, ends chunkEnds) g.Error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := &firstError{cancel: cancel}
+
+	jobs := make(chan chunkJob)
+	var wg sync.WaitGroup
+	for i := 0; i < g.jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if gerr := copyChunk(ctx, job); gerr != nil {
+					first.set(gerr)
+				}
+			}
+		}()
+	}
+
+	nFiles := 0
+dispatch:
+	for i, start := 0, int64(0); i < ends.n; i++ {
+		// the rest of the chunks are empty
+		if g.bElideEmptyFiles && start >= ends.fileSize {
+			break
+		}
+		end, gerr := ends.end(i)
+		if gerr != nil {
+			first.set(gerr)
+			break
+		}
+		chunkSize := end - start
+		chunkStart := start
+		start = end
+		if g.bElideEmptyFiles && chunkSize == 0 {
+			continue
+		}
+
+		w, gerr := g.createOutFile(nFiles)
+		if gerr != nil {
+			first.set(gerr)
+			break
+		}
+		nFiles++
+
+		job := chunkJob{w: w, sr: io.NewSectionReader(ra, chunkStart, chunkSize)}
+		select {
+		case jobs <- job:
+		case <-ctx.Done():
+			abortOutFile(w)
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return first.gerr
+}
+
+// firstError keeps the first error of the workers, canceling the others.
+type firstError struct {
+	once   sync.Once
+	gerr   g.Error
+	cancel context.CancelFunc
+}
+
+// set keeps gerr if it is the first one.
+func (f *firstError) set(gerr g.Error) {
+	f.once.Do(func() {
+		f.gerr = gerr
+		f.cancel()
+	})
+}
+
+// copyChunk writes the byte range of job to its output file, stopping when ctx is canceled.
+func copyChunk(ctx context.Context, job chunkJob) g.Error {
+	_, err := io.Copy(job.w, &contextReader{ctx: ctx, r: job.sr})
+	if err != nil {
+		abortOutFile(job.w)
+		return wrapper.Errorf("failed to write: %w", err)
+	}
+	if err := job.w.Close(); err != nil {
+		return wrapper.Errorf("failed to close: %w", err)
+	}
+	return nil
+}
+
+// contextReader fails reading after ctx is canceled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read implements io.Reader.
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
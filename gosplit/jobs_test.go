@@ -0,0 +1,155 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"testing"
+)
+
+func helperReadOutDir(t *testing.T, outDir string) map[string][]byte {
+	t.Helper()
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal("failed to read dir:", err)
+	}
+	files := map[string][]byte{}
+	for _, entry := range entries {
+		data, err := os.ReadFile(path.Join(outDir, entry.Name()))
+		if err != nil {
+			t.Fatal("failed to read:", err)
+		}
+		files[entry.Name()] = data
+	}
+	return files
+}
+
+func TestOptions_Jobs(t *testing.T) {
+	t.Parallel()
+
+	input := make([]byte, 1000003)
+	rand.New(rand.NewSource(1)).Read(input)
+	for i := range input {
+		// make lines for ByLineNumber
+		if input[i]%64 == 0 {
+			input[i] = '\n'
+		}
+	}
+	filePath := path.Join(t.TempDir(), "input")
+	if err := os.WriteFile(filePath, input, 0o644); err != nil {
+		t.Fatal("failed to write:", err)
+	}
+
+	cases := map[string]struct {
+		split func(g *gosplit.GoSplit) error
+		opts  gosplit.Options
+	}{
+		"ByBytes":          {func(g *gosplit.GoSplit) error { return g.ByBytes(65536) }, gosplit.Options{}},
+		"ByBytes exact":    {func(g *gosplit.GoSplit) error { return g.ByBytes(1000003) }, gosplit.Options{}},
+		"ByNumber":         {func(g *gosplit.GoSplit) error { return g.ByNumber(7) }, gosplit.Options{}},
+		"ByNumber elide":   {func(g *gosplit.GoSplit) error { return g.ByNumber(7) }, gosplit.Options{ElideEmptyFiles: true}},
+		"ByLineNumber":     {func(g *gosplit.GoSplit) error { return g.ByLineNumber(13) }, gosplit.Options{}},
+		"ByBytes compress": {func(g *gosplit.GoSplit) error { return g.ByBytes(100000) }, gosplit.Options{Compress: gosplit.CompressGzip}},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			outDirs := make([]string, 2)
+			for i, jobs := range []int{0, 4} {
+				opts := tt.opts
+				opts.OutDir = t.TempDir()
+				opts.Jobs = jobs
+				g := helperNew(t, filePath, "x", opts)
+				if err := tt.split(g); err != nil {
+					t.Fatal("split failed:", err)
+				}
+				outDirs[i] = opts.OutDir
+			}
+
+			want := helperReadOutDir(t, outDirs[0])
+			got := helperReadOutDir(t, outDirs[1])
+			if len(got) != len(want) {
+				t.Fatalf("%d output files, want %d", len(got), len(want))
+			}
+			for name, data := range want {
+				if !bytes.Equal(got[name], data) {
+					t.Errorf("output file %#v differs from the sequential one", name)
+				}
+			}
+		})
+	}
+}
+
+func TestOptions_Jobs_Empty(t *testing.T) {
+	t.Parallel()
+
+	sink := gosplit.NewMemorySink()
+	g := helperNew(t, "testdata/empty", "x", gosplit.Options{Sink: sink, Jobs: 4})
+	if err := g.ByBytes(10); err != nil {
+		t.Fatal("ByBytes() failed:", err)
+	}
+	if names := sink.Names(); len(names) != 0 {
+		t.Errorf("Names() = %#v, want empty", names)
+	}
+}
+
+// failingSink fails writing the output file of name.
+type failingSink struct {
+	*gosplit.MemorySink
+	name string
+}
+
+var errFailingSink = errors.New("failing sink")
+
+func (s failingSink) Create(name string) (io.WriteCloser, error) {
+	if name == s.name {
+		return failingWriter{}, nil
+	}
+	return s.MemorySink.Create(name)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errFailingSink }
+func (failingWriter) Close() error                { return nil }
+
+func TestOptions_Jobs_Error(t *testing.T) {
+	t.Parallel()
+
+	sink := failingSink{MemorySink: gosplit.NewMemorySink(), name: "xab"}
+	g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{Sink: sink, Jobs: 2})
+	err := g.ByBytes(1)
+	if !errors.Is(err, errFailingSink) {
+		t.Fatalf("ByBytes() = %#v, want %#v", err, errFailingSink)
+	}
+
+	// the rest of the output files are canceled
+	if got := len(sink.Names()); got >= 1455-1 {
+		t.Errorf("len(Names()) = %#v, want less than %#v", got, 1455-1)
+	}
+}
+
+func TestOptions_Jobs_Verbose(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{Sink: gosplit.NewMemorySink(), Jobs: 4, Verbose: &buf})
+	if err := g.ByNumber(3); err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+
+	// the output files are created in order
+	want := "creating file \"xaa\"\ncreating file \"xab\"\ncreating file \"xac\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("verbose = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,140 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Join writes the output files of prefix to w in the order of their suffixes, reversing the split with opts.
+// The output files are decompressed with opts.Compress.
+//
+// All files starting with prefix are checked before writing anything. ErrMissingChunk is returned on a gap
+// of the suffixes, and ErrUnexpectedChunk is returned on a file whose suffix is not generated with opts.
+func Join(w io.Writer, prefix string, opts Options) error {
+	gs, gerr := newGoSplit(prefix, opts)
+	if gerr != nil {
+		return gerr
+	}
+	if gs.filterCommand != "" {
+		return wrapper.Errorf("%w: Filter is given to Join", ErrInvalidSink)
+	}
+
+	outFilePaths, gerr := gs.listOutFiles()
+	if gerr != nil {
+		return gerr
+	}
+
+	for _, outFilePath := range outFilePaths {
+		fmt.Fprintf(gs.wVerbose, "reading file %#v\n", outFilePath)
+		if gerr := gs.copyOutFile(w, outFilePath); gerr != nil {
+			return gerr
+		}
+	}
+	return nil
+}
+
+// outFile is a file starting with the prefix, which may be an output file.
+type outFile struct {
+	name     string // prefix and suffix, as the same as the name passed to the sink
+	path     string
+	bRegular bool
+}
+
+// findOutFiles returns the files starting with the prefix in the directory of the sink.
+func (g *GoSplit) findOutFiles() ([]outFile, g.Error) {
+	ds, ok := g.sink.(*DirSink)
+	if !ok {
+		return nil, wrapper.Errorf("%w: output files are read only from a directory", ErrInvalidSink)
+	}
+
+	// the prefix may contain directories, e.g. "out/x"
+	dir, base := ds.Path(g.prefix), ""
+	if !strings.HasSuffix(g.prefix, "/") {
+		dir, base = path.Split(dir)
+	}
+	if dir == "" {
+		dir = "./"
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, wrapper.Errorf("failed to read directory: %w", err)
+	}
+
+	var files []outFile
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(entry.Name(), base)
+		if !ok {
+			continue
+		}
+		files = append(files, outFile{
+			name:     g.prefix + suffix,
+			path:     path.Join(dir, entry.Name()),
+			bRegular: entry.Type().IsRegular(),
+		})
+	}
+	return files, nil
+}
+
+// numberOutFiles returns the output files by their numbers, and the other files which are unexpected.
+func (g *GoSplit) numberOutFiles(files []outFile) (map[int]outFile, []outFile) {
+	numbered := map[int]outFile{}
+	var unexpected []outFile
+	for _, file := range files {
+		suffix, ok := strings.CutSuffix(strings.TrimPrefix(file.name, g.prefix), g.compression.Extension())
+		number, gerr := g.suffix.Parse(suffix)
+		if !ok || gerr != nil || !file.bRegular {
+			unexpected = append(unexpected, file)
+			continue
+		}
+		numbered[number] = file
+	}
+	return numbered, unexpected
+}
+
+// listOutFiles returns the paths of the existing output files in order, checking that no file is missing or unexpected.
+func (g *GoSplit) listOutFiles() ([]string, g.Error) {
+	files, gerr := g.findOutFiles()
+	if gerr != nil {
+		return nil, gerr
+	}
+
+	numbered, unexpected := g.numberOutFiles(files)
+	if len(unexpected) > 0 {
+		return nil, wrapper.Errorf("%w: %#v", ErrUnexpectedChunk, unexpected[0].path)
+	}
+
+	// the output files are numbered from 0 without gaps, even with ElideEmptyFiles
+	result := make([]string, 0, len(numbered))
+	for number := 0; number < len(numbered) || number == 0; number++ {
+		file, ok := numbered[number]
+		if !ok {
+			missing, gerr := g.generateOutFilePath(number)
+			if gerr != nil {
+				return nil, gerr
+			}
+			return nil, wrapper.Errorf("%w: %#v", ErrMissingChunk, missing)
+		}
+		result = append(result, file.path)
+	}
+	return result, nil
+}
+
+// copyOutFile writes the content of the output file of filePath to w, decompressing it if needed.
+func (g *GoSplit) copyOutFile(w io.Writer, filePath string) g.Error {
+	r, gerr := g.openOutFile(filePath)
+	if gerr != nil {
+		return gerr
+	}
+	defer r.Close()
+
+	if _, err := io.Copy(w, r); err != nil {
+		return wrapper.Errorf("failed to copy: %w", err)
+	}
+	return nil
+}
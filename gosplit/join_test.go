@@ -0,0 +1,132 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"bytes"
+	"errors"
+	"os"
+	"path"
+	"testing"
+)
+
+func helperSplitToDir(t *testing.T, opts gosplit.Options) string {
+	t.Helper()
+
+	opts.OutDir = t.TempDir()
+	g := helperNew(t, "testdata/example.txt", "x", opts)
+	if err := g.ByLines(10); err != nil {
+		t.Fatal("ByLines() failed:", err)
+	}
+	return opts.OutDir
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	want, err := os.ReadFile("testdata/example.txt")
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+
+	cases := map[string]gosplit.Options{
+		"default":    {},
+		"numeric":    {Suffix: gosplit.Suffix{Type: gosplit.SuffixNumeric, Start: 8}},
+		"length":     {Suffix: gosplit.Suffix{Length: 1}},
+		"additional": {Suffix: gosplit.Suffix{Additional: ".txt"}},
+	}
+
+	for name, opts := range cases {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			outDir := helperSplitToDir(t, opts)
+
+			var b bytes.Buffer
+			opts.OutDir = outDir
+			if err := gosplit.Join(&b, "x", opts); err != nil {
+				t.Fatal("Join() failed:", err)
+			}
+			if !bytes.Equal(b.Bytes(), want) {
+				t.Errorf("Join() wrote %d bytes, want the same %d bytes as the input", b.Len(), len(want))
+			}
+		})
+	}
+}
+
+func TestJoin_PrefixWithDir(t *testing.T) {
+	t.Parallel()
+
+	outDir := helperSplitToDir(t, gosplit.Options{})
+
+	var b bytes.Buffer
+	if err := gosplit.Join(&b, path.Join(outDir, "x"), gosplit.Options{}); err != nil {
+		t.Fatal("Join() failed:", err)
+	}
+	if got := b.Len(); got != 1455 {
+		t.Errorf("Join() wrote %d bytes, want %d", got, 1455)
+	}
+}
+
+func TestJoin_Invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		modify  func(outDir string) error
+		wantErr error
+	}{
+		"missing": {
+			func(outDir string) error { return os.Remove(path.Join(outDir, "xab")) },
+			gosplit.ErrMissingChunk,
+		},
+		"missing all": {
+			func(outDir string) error { return os.RemoveAll(outDir) },
+			nil,
+		},
+		"unexpected": {
+			func(outDir string) error { return os.WriteFile(path.Join(outDir, "xaa.orig"), nil, 0o644) },
+			gosplit.ErrUnexpectedChunk,
+		},
+		"unexpected widened": {
+			func(outDir string) error { return os.WriteFile(path.Join(outDir, "xzz"), nil, 0o644) },
+			gosplit.ErrUnexpectedChunk,
+		},
+		"directory": {
+			func(outDir string) error { return os.Mkdir(path.Join(outDir, "xaf"), 0o755) },
+			gosplit.ErrUnexpectedChunk,
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			outDir := helperSplitToDir(t, gosplit.Options{})
+			if err := tt.modify(outDir); err != nil {
+				t.Fatal("failed to modify:", err)
+			}
+
+			var b bytes.Buffer
+			err := gosplit.Join(&b, "x", gosplit.Options{OutDir: outDir})
+			if err == nil {
+				t.Fatal("want err")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Join() = %#v, want %#v", err, tt.wantErr)
+			}
+			if b.Len() != 0 {
+				t.Errorf("Join() wrote %d bytes before the error", b.Len())
+			}
+		})
+	}
+}
+
+func TestJoin_Empty(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	err := gosplit.Join(&b, "x", gosplit.Options{OutDir: t.TempDir()})
+	if !errors.Is(err, gosplit.ErrMissingChunk) {
+		t.Errorf("Join() = %#v, want %#v", err, gosplit.ErrMissingChunk)
+	}
+}
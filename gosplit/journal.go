@@ -0,0 +1,347 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// journalSampleSize is the size of the head and the tail of the input hashed to identify it.
+const journalSampleSize = 64 * 1024
+
+// journalHeader is the first line of the journal, identifying the input and the split.
+type journalHeader struct {
+	Input  journalInput  `json:"input"`
+	Params journalParams `json:"params"`
+}
+
+// journalInput identifies the input, which must not change between the runs.
+type journalInput struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"`
+	FileID  uint64 `json:"file_id"`
+	SHA256  string `json:"partial_sha256"` // the hash of the head and the tail
+}
+
+// journalParams are the parameters of the split, which must not change between the runs.
+type journalParams struct {
+	Mode            string      `json:"mode"`
+	Value           int64       `json:"value"`
+	Prefix          string      `json:"prefix"`
+	Suffix          Suffix      `json:"suffix"`
+	Separator       string      `json:"separator"`
+	Compress        Compression `json:"compress"`
+	CompressLevel   int         `json:"compress_level"`
+	CountCompressed bool        `json:"count_compressed"`
+}
+
+// journalChunk is a line of the journal recording a completed output file.
+type journalChunk struct {
+	Name   string `json:"name"`
+	Index  int    `json:"index"`
+	End    int64  `json:"end"` // the offset of the input after the output file
+	Length int64  `json:"length"`
+	SHA256 string `json:"sha256"`
+}
+
+// journal records the completed output files of a resumable split.
+//
+// An output file is recorded only when the next one is created or the split succeeds, since it may be incomplete
+// when it is closed on an error.
+type journal struct {
+	filePath string
+	f        *os.File
+	end      int64
+	pending  *journalChunk
+}
+
+// journalPath returns the path of the journal, which is hidden next to the output files.
+func (g *GoSplit) journalPath(ds *DirSink) string {
+	dir, base := path.Split(g.prefix)
+	return ds.Path(dir + "." + base + ".gosplit-journal")
+}
+
+// startJournal resumes the split of mode and value from the journal if it is enabled, or starts a new journal.
+//
+// The output files recorded in the journal are checked, and the input is seeked to the end of the last valid one.
+// It fails with ErrJournalMismatch if the input or the parameters have changed since the journal was written.
+func (g *GoSplit) startJournal(in *input, mode string, value int64) g.Error {
+	if !g.bResume {
+		return nil
+	}
+
+	ds, ok := g.sink.(*DirSink)
+	switch {
+	case !ok || g.filterCommand != "":
+		return wrapper.Errorf("%w: output files are not written to a directory", ErrCannotResume)
+	case g.wManifest != nil:
+		return wrapper.Errorf("%w: with manifest", ErrCannotResume)
+	case in.f == nil || in.rs == nil:
+		return wrapper.Errorf("%w: input is not a regular file", ErrCannotResume)
+	}
+
+	identity, gerr := identifyInput(in)
+	if gerr != nil {
+		return gerr
+	}
+	header := journalHeader{
+		Input: identity,
+		Params: journalParams{
+			Mode:            mode,
+			Value:           value,
+			Prefix:          g.prefix,
+			Suffix:          g.suffix,
+			Separator:       string(g.separator),
+			Compress:        g.compression,
+			CompressLevel:   g.compressLevel,
+			CountCompressed: g.bCountCompressed,
+		},
+	}
+
+	filePath := g.journalPath(ds)
+	chunks, gerr := g.readJournal(filePath, header)
+	if gerr != nil {
+		return gerr
+	}
+	chunks = g.checkJournalChunks(ds, chunks)
+
+	// the journal is rewritten without the invalid output files
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.Encode(header)
+	for _, chunk := range chunks {
+		enc.Encode(chunk)
+	}
+	if gerr := writeFileAtomic(filePath, buf.Bytes()); gerr != nil {
+		return gerr
+	}
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return wrapper.Errorf("failed to open journal: %w", err)
+	}
+
+	end := int64(0)
+	if len(chunks) > 0 {
+		end = chunks[len(chunks)-1].End
+		fmt.Fprintf(g.wVerbose, "resuming after file %#v\n", ds.Path(chunks[len(chunks)-1].Name))
+	}
+	if _, err := in.rs.Seek(end, io.SeekStart); err != nil {
+		f.Close()
+		return wrapper.Errorf("failed to seek: %w", err)
+	}
+
+	g.journal = &journal{filePath: filePath, f: f, end: end}
+	g.firstNumber = len(chunks)
+	return nil
+}
+
+// identifyInput returns the identity of the input.
+func identifyInput(in *input) (journalInput, g.Error) {
+	fi, err := in.f.Stat()
+	if err != nil {
+		return journalInput{}, wrapper.Errorf("failed to stat: %w", err)
+	}
+	fileID, gerr := getFileID(in.f)
+	if gerr != nil {
+		return journalInput{}, gerr
+	}
+
+	ra, ok := in.rs.(io.ReaderAt)
+	if !ok {
+		return journalInput{}, wrapper.Errorf("%w: input is not a regular file", ErrCannotResume)
+	}
+	h := sha256.New()
+	head := io.NewSectionReader(ra, 0, min(in.size, journalSampleSize))
+	tail := io.NewSectionReader(ra, max(in.size-journalSampleSize, 0), min(in.size, journalSampleSize))
+	if _, err := io.Copy(h, io.MultiReader(head, tail)); err != nil {
+		return journalInput{}, wrapper.Errorf("failed to read: %w", err)
+	}
+
+	return journalInput{
+		Size:    in.size,
+		ModTime: fi.ModTime().UnixNano(),
+		FileID:  fileID,
+		SHA256:  hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// readJournal returns the output files recorded in the journal of filePath, checking that its header is header.
+// No output file is returned if the journal does not exist.
+func (g *GoSplit) readJournal(filePath string, header journalHeader) ([]journalChunk, g.Error) {
+	f, err := os.Open(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapper.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	var got journalHeader
+	if !scanner.Scan() || json.Unmarshal(scanner.Bytes(), &got) != nil {
+		return nil, wrapper.Errorf("%w: %#v is broken", ErrJournalMismatch, filePath)
+	}
+	if got.Params != header.Params {
+		return nil, wrapper.Errorf("%w: the options differ from %#v", ErrJournalMismatch, filePath)
+	}
+	if got.Input != header.Input {
+		return nil, wrapper.Errorf("%w: the input has changed since %#v was written", ErrJournalMismatch, filePath)
+	}
+
+	var chunks []journalChunk
+	for scanner.Scan() {
+		// the last line may be written partially
+		var chunk journalChunk
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			break
+		}
+		chunks = append(chunks, chunk)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, wrapper.Errorf("failed to read journal: %w", err)
+	}
+	return chunks, nil
+}
+
+// checkJournalChunks returns the leading output files of chunks which are intact and contiguous.
+func (g *GoSplit) checkJournalChunks(ds *DirSink, chunks []journalChunk) []journalChunk {
+	end := int64(0)
+	for i, chunk := range chunks {
+		filePath := ds.Path(chunk.Name)
+		name, gerr := g.generateOutFileName(i)
+		if gerr != nil || chunk.Name != name || chunk.Index != i || chunk.End != end+chunk.Length {
+			return chunks[:i]
+		}
+
+		fmt.Fprintf(g.wVerbose, "checking file %#v\n", filePath)
+		size, sum, gerr := g.hashOutFile(filePath)
+		if gerr != nil || size != chunk.Length || sum != chunk.SHA256 {
+			return chunks[:i]
+		}
+		end = chunk.End
+	}
+	return chunks
+}
+
+// writeFileAtomic writes data to the file of filePath through a temporary file.
+func writeFileAtomic(filePath string, data []byte) g.Error {
+	f, err := createAtomicFile(filePath)
+	if err != nil {
+		return wrapper.Errorf("%w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return wrapper.Errorf("failed to write journal: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return wrapper.Errorf("%w", err)
+	}
+	return nil
+}
+
+// journalWriter records the output file written through it to the journal on Close.
+type journalWriter struct {
+	io.WriteCloser
+	j       *journal
+	name    string
+	index   int
+	counter *lineCounter
+}
+
+// journalOutFile returns the writer of w recording the n-th output file of name, if the journal is enabled.
+func (g *GoSplit) journalOutFile(w io.WriteCloser, name string, number int) io.WriteCloser {
+	if g.journal == nil {
+		return w
+	}
+
+	return &journalWriter{WriteCloser: w, j: g.journal, name: name, index: number, counter: newLineCounter(g.separator)}
+}
+
+// Write implements io.Writer.
+func (jw *journalWriter) Write(p []byte) (int, error) {
+	n, err := jw.WriteCloser.Write(p)
+	jw.counter.Write(p[:n])
+	return n, err
+}
+
+// outSize implements sizer if the underlying writer does.
+func (jw *journalWriter) outSize() (int64, error) {
+	if s, ok := jw.WriteCloser.(sizer); ok {
+		return s.outSize()
+	}
+	return jw.counter.size, nil
+}
+
+// Abort implements aborter, discarding the output file without recording it.
+func (jw *journalWriter) Abort() error {
+	return abortOutFile(jw.WriteCloser)
+}
+
+// Close implements io.Closer, keeping the output file to be recorded when it is closed successfully.
+func (jw *journalWriter) Close() error {
+	if err := jw.WriteCloser.Close(); err != nil {
+		return err
+	}
+
+	jw.j.pending = &journalChunk{
+		Name:   jw.name,
+		Index:  jw.index,
+		End:    jw.j.end + jw.counter.size,
+		Length: jw.counter.size,
+		SHA256: jw.counter.sum(),
+	}
+	jw.j.end += jw.counter.size
+	return nil
+}
+
+// commit records the output file closed last, which is known to be complete.
+func (j *journal) commit() g.Error {
+	if j.pending == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(j.pending)
+	if err != nil {
+		return wrapper.Errorf("failed to write journal: %w", err)
+	}
+	if _, err := j.f.Write(append(line, '\n')); err != nil {
+		return wrapper.Errorf("failed to write journal: %w", err)
+	}
+	if err := j.f.Sync(); err != nil {
+		return wrapper.Errorf("failed to write journal: %w", err)
+	}
+	j.pending = nil
+	return nil
+}
+
+// finishJournal closes the journal, removing it if the split has succeeded.
+func (g *GoSplit) finishJournal(bSucceeded bool) g.Error {
+	j := g.journal
+	g.journal = nil
+	g.firstNumber = 0
+	if j == nil {
+		return nil
+	}
+
+	if err := j.f.Close(); err != nil && bSucceeded {
+		return wrapper.Errorf("failed to close journal: %w", err)
+	}
+	if !bSucceeded {
+		return nil
+	}
+	if err := os.Remove(j.filePath); err != nil {
+		return wrapper.Errorf("failed to remove journal: %w", err)
+	}
+	return nil
+}
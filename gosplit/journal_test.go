@@ -0,0 +1,174 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+// helperInterruptedSplit splits filePath with Resume into outDir, failing at the output file of blocker.
+func helperInterruptedSplit(t *testing.T, filePath string, outDir string, blocker string, split func(g *gosplit.GoSplit) error) {
+	t.Helper()
+
+	// an output file cannot be renamed to the directory
+	blockerPath := path.Join(outDir, blocker)
+	if err := os.Mkdir(blockerPath, 0o755); err != nil {
+		t.Fatal("failed to mkdir:", err)
+	}
+	g := helperNew(t, filePath, "x", gosplit.Options{OutDir: outDir, Resume: true})
+	if err := split(g); err == nil {
+		t.Fatal("split should fail")
+	}
+	if err := os.Remove(blockerPath); err != nil {
+		t.Fatal("failed to remove:", err)
+	}
+}
+
+func TestOptions_Resume(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		split func(g *gosplit.GoSplit) error
+	}{
+		"ByLines":     {func(g *gosplit.GoSplit) error { return g.ByLines(5) }},
+		"ByBytes":     {func(g *gosplit.GoSplit) error { return g.ByBytes(100) }},
+		"ByLineBytes": {func(g *gosplit.GoSplit) error { return g.ByLineBytes(100) }},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			wantDir := t.TempDir()
+			if err := tt.split(helperNew(t, "testdata/example.txt", "x", gosplit.Options{OutDir: wantDir})); err != nil {
+				t.Fatal("split failed:", err)
+			}
+
+			outDir := t.TempDir()
+			helperInterruptedSplit(t, "testdata/example.txt", outDir, "xad", tt.split)
+
+			var verbose bytes.Buffer
+			g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{OutDir: outDir, Resume: true, Verbose: &verbose})
+			if err := tt.split(g); err != nil {
+				t.Fatal("resumed split failed:", err)
+			}
+			if !strings.Contains(verbose.String(), "resuming after file") {
+				t.Errorf("verbose = %#v, want resuming", verbose.String())
+			}
+			// the intact output files are not rewritten
+			if rewritten := fmt.Sprintf("creating file %#v", path.Join(outDir, "xab")); strings.Contains(verbose.String(), rewritten) {
+				t.Errorf("verbose = %#v, want no %#v", verbose.String(), rewritten)
+			}
+
+			want := helperReadOutDir(t, wantDir)
+			got := helperReadOutDir(t, outDir)
+			if len(got) != len(want) {
+				t.Fatalf("%d files in the output directory, want %d", len(got), len(want))
+			}
+			for name, data := range want {
+				if !bytes.Equal(got[name], data) {
+					t.Errorf("output file %#v differs from the split without interruption", name)
+				}
+			}
+		})
+	}
+}
+
+func TestOptions_Resume_ModifiedChunk(t *testing.T) {
+	t.Parallel()
+
+	split := func(g *gosplit.GoSplit) error { return g.ByLines(5) }
+	wantDir := t.TempDir()
+	if err := split(helperNew(t, "testdata/example.txt", "x", gosplit.Options{OutDir: wantDir})); err != nil {
+		t.Fatal("split failed:", err)
+	}
+
+	outDir := t.TempDir()
+	helperInterruptedSplit(t, "testdata/example.txt", outDir, "xae", split)
+	if err := os.WriteFile(path.Join(outDir, "xab"), []byte("modified\n"), 0o644); err != nil {
+		t.Fatal("failed to write:", err)
+	}
+
+	// the split is resumed from the modified output file
+	if err := split(helperNew(t, "testdata/example.txt", "x", gosplit.Options{OutDir: outDir, Resume: true})); err != nil {
+		t.Fatal("resumed split failed:", err)
+	}
+	want := helperReadOutDir(t, wantDir)
+	got := helperReadOutDir(t, outDir)
+	for name, data := range want {
+		if !bytes.Equal(got[name], data) {
+			t.Errorf("output file %#v differs from the split without interruption", name)
+		}
+	}
+}
+
+func TestOptions_Resume_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile("testdata/example.txt")
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+
+	cases := map[string]struct {
+		modify func(t *testing.T, filePath string)
+		split  func(g *gosplit.GoSplit) error
+	}{
+		"input changed": {
+			func(t *testing.T, filePath string) {
+				if err := os.WriteFile(filePath, append(content, "appended\n"...), 0o644); err != nil {
+					t.Fatal("failed to write:", err)
+				}
+			},
+			func(g *gosplit.GoSplit) error { return g.ByBytes(100) },
+		},
+		"options changed": {
+			func(t *testing.T, filePath string) {},
+			func(g *gosplit.GoSplit) error { return g.ByBytes(200) },
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filePath := path.Join(t.TempDir(), "input")
+			if err := os.WriteFile(filePath, content, 0o644); err != nil {
+				t.Fatal("failed to write:", err)
+			}
+			outDir := t.TempDir()
+			helperInterruptedSplit(t, filePath, outDir, "xad", func(g *gosplit.GoSplit) error { return g.ByBytes(100) })
+
+			tt.modify(t, filePath)
+			err := tt.split(helperNew(t, filePath, "x", gosplit.Options{OutDir: outDir, Resume: true}))
+			if !errors.Is(err, gosplit.ErrJournalMismatch) {
+				t.Errorf("split = %#v, want %#v", err, gosplit.ErrJournalMismatch)
+			}
+		})
+	}
+}
+
+func TestOptions_Resume_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{OutDir: t.TempDir(), Resume: true})
+	if err := g.ByNumber(2); !errors.Is(err, gosplit.ErrCannotResume) {
+		t.Errorf("ByNumber() = %#v, want %#v", err, gosplit.ErrCannotResume)
+	}
+
+	g, gerr := gosplit.NewReader(strings.NewReader("a\n"), "x", gosplit.Options{OutDir: t.TempDir(), Resume: true})
+	if gerr != nil {
+		t.Fatal("NewReader() failed:", gerr)
+	}
+	if err := g.ByLines(1); !errors.Is(err, gosplit.ErrCannotResume) {
+		t.Errorf("ByLines() = %#v, want %#v", err, gosplit.ErrCannotResume)
+	}
+}
@@ -0,0 +1,200 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"sort"
+)
+
+// Manifest describes the input and the output files of a split.
+type Manifest struct {
+	Input  ManifestInput   `json:"input"`
+	Chunks []ManifestChunk `json:"chunks"`
+}
+
+// ManifestInput describes the input of a split.
+type ManifestInput struct {
+	// Size is the number of bytes read from the input.
+	Size int64 `json:"size"`
+	// Lines is the number of lines/records including the last one without the separator.
+	Lines int64 `json:"lines"`
+	// SHA256 is the hex-encoded SHA-256 hash of the input.
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestChunk describes an output file of a split.
+type ManifestChunk struct {
+	// Name is the output file name passed to the sink, i.e. prefix and suffix.
+	Name string `json:"name"`
+	// Index is the number of the output file starting at 0.
+	Index int `json:"index"`
+	// Offset is the byte offset of the output file in the input, or -1 with round robin.
+	Offset int64 `json:"offset"`
+	// Length is the number of bytes of the output file, before compression with Compress.
+	Length int64 `json:"length"`
+	// FirstLine and LastLine are the line range of the output file starting at 1, including both ends.
+	// LastLine is less than FirstLine if the output file is empty. Both are -1 with round robin.
+	FirstLine int64 `json:"first_line"`
+	LastLine  int64 `json:"last_line"`
+	// SHA256 is the hex-encoded SHA-256 hash of the output file. It is the hash of the uncompressed content
+	// with Compress, as the same as Length, which Verify checks after decompressing the output file.
+	SHA256 string `json:"sha256"`
+}
+
+// lineCounter hashes and counts the bytes and the lines written to it.
+type lineCounter struct {
+	hash     hash.Hash
+	sep      byte
+	size     int64
+	nSeps    int64
+	lastByte byte
+}
+
+// newLineCounter returns a new lineCounter counting the lines terminated by sep.
+func newLineCounter(sep byte) *lineCounter {
+	return &lineCounter{hash: sha256.New(), sep: sep}
+}
+
+// Write implements io.Writer.
+func (c *lineCounter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	c.hash.Write(p)
+	c.size += int64(len(p))
+	c.nSeps += int64(bytes.Count(p, []byte{c.sep}))
+	c.lastByte = p[len(p)-1]
+	return len(p), nil
+}
+
+// bPartialLine returns whether the content ends in the middle of a line.
+func (c *lineCounter) bPartialLine() bool {
+	return c.size > 0 && c.lastByte != c.sep
+}
+
+// sum returns the hex-encoded hash of the content.
+func (c *lineCounter) sum() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
+
+// manifestRecorder records Manifest while splitting.
+type manifestRecorder struct {
+	input       *lineCounter
+	bContiguous bool
+	chunks      []ManifestChunk
+	offset      int64
+	nLines      int64 // the number of complete lines before offset
+}
+
+// trackInput starts recording Manifest if it is enabled, returning the reader to split instead of r.
+//
+// bContiguous tells whether the output files are contiguous ranges of the input, i.e. not round robin.
+func (g *GoSplit) trackInput(r io.Reader, bContiguous bool) io.Reader {
+	if g.wManifest == nil {
+		return r
+	}
+
+	g.manifest = &manifestRecorder{input: newLineCounter(g.separator), bContiguous: bContiguous}
+	return io.TeeReader(r, g.manifest.input)
+}
+
+// manifestWriter records the output file written through it to manifestRecorder on Close.
+type manifestWriter struct {
+	io.WriteCloser
+	rec     *manifestRecorder
+	name    string
+	index   int
+	counter *lineCounter
+}
+
+// trackOutFile returns the writer of w recording the n-th output file of name, if Manifest is enabled.
+func (g *GoSplit) trackOutFile(w io.WriteCloser, name string, number int) io.WriteCloser {
+	if g.manifest == nil {
+		return w
+	}
+
+	return &manifestWriter{WriteCloser: w, rec: g.manifest, name: name, index: number, counter: newLineCounter(g.separator)}
+}
+
+// Write implements io.Writer.
+func (m *manifestWriter) Write(p []byte) (int, error) {
+	n, err := m.WriteCloser.Write(p)
+	m.counter.Write(p[:n])
+	return n, err
+}
+
+// Abort implements aborter, discarding the output file without recording it.
+func (m *manifestWriter) Abort() error {
+	return abortOutFile(m.WriteCloser)
+}
+
+// Close implements io.Closer, recording the output file when it is closed successfully.
+func (m *manifestWriter) Close() error {
+	if err := m.WriteCloser.Close(); err != nil {
+		return err
+	}
+
+	rec := m.rec
+	chunk := ManifestChunk{
+		Name:      m.name,
+		Index:     m.index,
+		Offset:    -1,
+		Length:    m.counter.size,
+		FirstLine: -1,
+		LastLine:  -1,
+		SHA256:    m.counter.sum(),
+	}
+	if rec.bContiguous {
+		chunk.Offset = rec.offset
+		chunk.FirstLine = rec.nLines + 1
+		chunk.LastLine = rec.nLines + m.counter.nSeps
+		if m.counter.bPartialLine() {
+			chunk.LastLine++
+		}
+		rec.offset += m.counter.size
+		rec.nLines += m.counter.nSeps
+	}
+	rec.chunks = append(rec.chunks, chunk)
+	return nil
+}
+
+// writeManifest writes the recorded Manifest as JSON, if it is enabled.
+func (g *GoSplit) writeManifest() g.Error {
+	rec := g.manifest
+	if rec == nil {
+		return nil
+	}
+	g.manifest = nil
+
+	manifest := Manifest{
+		Input: ManifestInput{
+			Size:   rec.input.size,
+			Lines:  rec.input.nSeps,
+			SHA256: rec.input.sum(),
+		},
+		Chunks: rec.chunks,
+	}
+	if rec.input.bPartialLine() {
+		manifest.Input.Lines++
+	}
+	if manifest.Chunks == nil {
+		manifest.Chunks = []ManifestChunk{}
+	}
+	sort.Slice(manifest.Chunks, func(i, j int) bool {
+		return manifest.Chunks[i].Index < manifest.Chunks[j].Index
+	})
+
+	enc := json.NewEncoder(g.wManifest)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return wrapper.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"testing"
+)
+
+func helperSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func helperSplitWithManifest(t *testing.T, split func(g *gosplit.GoSplit) error) (*gosplit.MemorySink, gosplit.Manifest) {
+	t.Helper()
+
+	sink := gosplit.NewMemorySink()
+	var b bytes.Buffer
+	g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{Sink: sink, Manifest: &b})
+	if err := split(g); err != nil {
+		t.Fatal("split failed:", err)
+	}
+
+	var manifest gosplit.Manifest
+	if err := json.Unmarshal(b.Bytes(), &manifest); err != nil {
+		t.Fatal("failed to decode manifest:", err)
+	}
+	return sink, manifest
+}
+
+func TestManifest(t *testing.T) {
+	t.Parallel()
+
+	input, err := os.ReadFile("testdata/example.txt")
+	if err != nil {
+		t.Fatal("failed to read:", err)
+	}
+
+	cases := map[string]struct {
+		split       func(g *gosplit.GoSplit) error
+		nChunks     int
+		bContiguous bool
+	}{
+		"ByLines":      {func(g *gosplit.GoSplit) error { return g.ByLines(10) }, 5, true},
+		"ByNumber":     {func(g *gosplit.GoSplit) error { return g.ByNumber(4) }, 4, true},
+		"ByLineNumber": {func(g *gosplit.GoSplit) error { return g.ByLineNumber(4) }, 4, true},
+		"ByRoundRobin": {func(g *gosplit.GoSplit) error { return g.ByRoundRobin(4) }, 4, false},
+		"ByBytes":      {func(g *gosplit.GoSplit) error { return g.ByBytes(500) }, 3, true},
+		"ByLineBytes":  {func(g *gosplit.GoSplit) error { return g.ByLineBytes(500) }, 4, true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			sink, manifest := helperSplitWithManifest(t, tt.split)
+
+			wantInput := gosplit.ManifestInput{Size: int64(len(input)), Lines: 42, SHA256: helperSHA256(input)}
+			if manifest.Input != wantInput {
+				t.Errorf("Input = %#v, want %#v", manifest.Input, wantInput)
+			}
+			if len(manifest.Chunks) != tt.nChunks {
+				t.Fatalf("len(Chunks) = %d, want %d", len(manifest.Chunks), tt.nChunks)
+			}
+
+			offset := int64(0)
+			for i, chunk := range manifest.Chunks {
+				content, ok := sink.Bytes(chunk.Name)
+				if !ok {
+					t.Fatalf("output file %#v is not found", chunk.Name)
+				}
+				if chunk.Index != i || chunk.Length != int64(len(content)) || chunk.SHA256 != helperSHA256(content) {
+					t.Errorf("Chunks[%d] = %#v, not match the output file", i, chunk)
+				}
+
+				wantOffset := int64(-1)
+				if tt.bContiguous {
+					wantOffset = offset
+				}
+				if chunk.Offset != wantOffset {
+					t.Errorf("Chunks[%d].Offset = %d, want %d", i, chunk.Offset, wantOffset)
+				}
+				offset += chunk.Length
+			}
+		})
+	}
+}
+
+func TestManifest_LineRange(t *testing.T) {
+	t.Parallel()
+
+	_, manifest := helperSplitWithManifest(t, func(g *gosplit.GoSplit) error { return g.ByBytes(500) })
+
+	// the line 12 and 29 are split into two output files
+	want := [][2]int64{{1, 12}, {12, 29}, {30, 42}}
+	for i, chunk := range manifest.Chunks {
+		if got := [2]int64{chunk.FirstLine, chunk.LastLine}; got != want[i] {
+			t.Errorf("Chunks[%d] line range = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestManifest_EmptyFile(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	g := helperNew(t, "testdata/empty", "x", gosplit.Options{Sink: gosplit.NewMemorySink(), Manifest: &b})
+	if err := g.ByNumber(2); err != nil {
+		t.Fatal("ByNumber() failed:", err)
+	}
+
+	var manifest gosplit.Manifest
+	if err := json.Unmarshal(b.Bytes(), &manifest); err != nil {
+		t.Fatal("failed to decode manifest:", err)
+	}
+	for i, chunk := range manifest.Chunks {
+		if chunk.Length != 0 || chunk.FirstLine != 1 || chunk.LastLine != 0 {
+			t.Errorf("Chunks[%d] = %#v, want an empty line range", i, chunk)
+		}
+	}
+	if manifest.Input.Size != 0 || manifest.Input.Lines != 0 {
+		t.Errorf("Input = %#v, want empty", manifest.Input)
+	}
+}
+
+func TestManifest_Compress(t *testing.T) {
+	t.Parallel()
+
+	// Length and SHA256 are of the content before compression
+	cases := map[string]struct {
+		compression gosplit.Compression
+		ext         string
+	}{
+		"none": {gosplit.CompressNone, ""},
+		"gzip": {gosplit.CompressGzip, ".gz"},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sink := gosplit.NewMemorySink()
+			var b bytes.Buffer
+			g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{Sink: sink, Manifest: &b, Compress: tt.compression})
+			if err := g.ByLines(20); err != nil {
+				t.Fatal("ByLines() failed:", err)
+			}
+
+			var manifest gosplit.Manifest
+			if err := json.Unmarshal(b.Bytes(), &manifest); err != nil {
+				t.Fatal("failed to decode manifest:", err)
+			}
+			if len(manifest.Chunks) != 3 {
+				t.Fatalf("len(Chunks) = %d, want %d", len(manifest.Chunks), 3)
+			}
+
+			for i, chunk := range manifest.Chunks {
+				if path.Ext(chunk.Name) != tt.ext {
+					t.Errorf("Chunks[%d].Name = %#v, want the extension %#v", i, chunk.Name, tt.ext)
+				}
+				written, ok := sink.Bytes(chunk.Name)
+				if !ok {
+					t.Fatalf("output file %#v is not found", chunk.Name)
+				}
+
+				content := written
+				if tt.compression != gosplit.CompressNone {
+					zr, err := gzip.NewReader(bytes.NewReader(written))
+					if err != nil {
+						t.Fatal("failed to decompress:", err)
+					}
+					if content, err = io.ReadAll(zr); err != nil {
+						t.Fatal("failed to decompress:", err)
+					}
+					if chunk.SHA256 == helperSHA256(written) {
+						t.Errorf("Chunks[%d].SHA256 is the hash of the compressed output file", i)
+					}
+				}
+				if chunk.Length != int64(len(content)) || chunk.SHA256 != helperSHA256(content) {
+					t.Errorf("Chunks[%d] = %#v, not match the uncompressed content", i, chunk)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"io"
+)
+
+// Options represents the options of GoSplit.
+//
+// The zero value is the same as the default behavior of GNU split.
+type Options struct {
+	// OutDir is the directory of output files. The current directory is used if empty.
+	OutDir string
+	// Sink is the destination of output files instead of OutDir, e.g. TarSink. NewDirSink(OutDir) is used if nil.
+	Sink Sink
+	// Suffix generates the suffixes of output file names.
+	Suffix Suffix
+	// ElideEmptyFiles does not generate empty output files with "-n" modes.
+	ElideEmptyFiles bool
+	// Separator is the single-byte separator of lines/records. "\n" is used if empty.
+	Separator string
+	// Filter is the shell command to write each chunk to, instead of the output file.
+	// The environment variable FILE is set to the output file name for the command.
+	Filter string
+	// Output is the writer for the extracted chunk and the filter command. os.Stdout is used if nil.
+	Output io.Writer
+	// Compress compresses each output file, appending the extension of the format to its name.
+	Compress Compression
+	// CompressLevel is the compression level from 1 (best speed) to 9 (best compression),
+	// or -2 for Huffman-only compression. The default level is used if 0.
+	CompressLevel int
+	// CountCompressed makes ByBytes and ByLineBytes count the compressed bytes instead of the input bytes.
+	// The input is copied only as much as its compressed size cannot exceed the rest of the size even if it is
+	// incompressible, i.e. with the overhead of stored blocks, and then the compressor is flushed to measure it.
+	// So an output file does not exceed the size unless the size is too small for the header and the trailer,
+	// and it is a little smaller than the size for the incompressible input.
+	CountCompressed bool
+	// Decompress makes the input compressed with gzip, bzip2 or zlib decompressed on the fly, detected by its magic bytes.
+	// The decompressed input is spooled into a temporary file if ByNumber and the similar methods need its size.
+	Decompress bool
+	// Jobs is the number of output files written concurrently by ByBytes, ByNumber and ByLineNumber
+	// when the input can be read at offsets, e.g. a regular file. The output files are written sequentially if 0 or 1,
+	// or with Manifest, Filter or CountCompressed.
+	Jobs int
+	// KeepPartial keeps the complete output files created in the directory by a failed split,
+	// which are removed by default. The incomplete one is always removed.
+	KeepPartial bool
+	// Resume makes ByLines, ByBytes and ByLineBytes resumable with a journal hidden next to the output files.
+	// A rerun with the same options checks the output files recorded in the journal and carries on after them,
+	// or fails with ErrJournalMismatch if the input has changed. The journal is removed after splitting successfully.
+	// The input must be a regular file, and the other methods fail with ErrCannotResume.
+	Resume bool
+	// Spool makes ByNumber and the similar methods copy the input of unknown size, e.g. a pipe,
+	// into a temporary file in OutDir, or $TMPDIR if OutDir is empty, instead of failing with ErrUnknownSize.
+	Spool bool
+	// InputSize is the size of the input of unknown size, if it is known in advance, e.g. a pipe of known length.
+	// ByNumber splits the input on the fly with it, and fails with ErrUnknownSize if the input is not of the size.
+	InputSize int64
+	// Progress is called with the progress of a split periodically while writing output files, and when it starts
+	// and ends. It is called from the goroutine of the split, or the workers of Jobs one at a time.
+	Progress func(Progress)
+	// Manifest is the writer of the JSON Manifest of the input and output files, written after splitting successfully.
+	// No manifest is written by the methods outputting a single chunk such as ExtractByNumber.
+	Manifest io.Writer
+	// Verbose is the writer for a diagnostic just before each output file is opened. Discarded if nil.
+	Verbose io.Writer
+}
+
+// Validate checks that all options are valid.
+func (o Options) Validate() error {
+	return o.validate()
+}
+
+// validate is the same as Validate, returning g.Error for the callers in this package.
+func (o Options) validate() g.Error {
+	if err := o.Suffix.validate(); err != nil {
+		return err
+	}
+
+	if err := o.Compress.validate(o.CompressLevel); err != nil {
+		return err
+	}
+
+	if o.Sink != nil && o.OutDir != "" {
+		return wrapper.Errorf("%w: both OutDir and Sink are given", ErrInvalidSink)
+	}
+	if o.Sink != nil && o.Filter != "" {
+		return wrapper.Errorf("%w: both Filter and Sink are given", ErrInvalidSink)
+	}
+
+	if o.InputSize < 0 {
+		return wrapper.Errorf("%w: input size %#v", ErrInvalidBytes, o.InputSize)
+	}
+
+	if o.Jobs < 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidJobs, o.Jobs)
+	}
+
+	if len(o.Separator) > 1 {
+		return wrapper.Errorf("%w: %#v: multi-character separator", ErrInvalidSeparator, o.Separator)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"errors"
+	"testing"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	filePath := "testdata/example.txt"
+	prefix := "TestOptions_Validate-"
+	cases := map[string]struct {
+		opts    gosplit.Options
+		wantErr error
+	}{
+		"zero":                 {gosplit.Options{}, nil},
+		"all":                  {gosplit.Options{OutDir: "out", Suffix: gosplit.Suffix{Type: gosplit.SuffixHex, Length: 3, Start: 1, Additional: ".txt"}, ElideEmptyFiles: true, Separator: "\x00", Filter: "cat"}, nil},
+		"unknown suffix type":  {gosplit.Options{Suffix: gosplit.Suffix{Type: gosplit.SuffixType(-1)}}, gosplit.ErrInvalidSuffix},
+		"negative length":      {gosplit.Options{Suffix: gosplit.Suffix{Length: -1}}, gosplit.ErrInvalidSuffixLength},
+		"negative start":       {gosplit.Options{Suffix: gosplit.Suffix{Start: -1}}, gosplit.ErrInvalidSuffixStart},
+		"additional with dir":  {gosplit.Options{Suffix: gosplit.Suffix{Additional: "dir/.txt"}}, gosplit.ErrInvalidSuffix},
+		"multi-char separator": {gosplit.Options{Separator: "\r\n"}, gosplit.ErrInvalidSeparator},
+		"compress":             {gosplit.Options{Compress: gosplit.CompressGzip, CompressLevel: 9, CountCompressed: true}, nil},
+		"unknown compression":  {gosplit.Options{Compress: gosplit.Compression(-1)}, gosplit.ErrInvalidCompression},
+		"compression level":    {gosplit.Options{Compress: gosplit.CompressZlib, CompressLevel: 10}, gosplit.ErrInvalidCompression},
+		"sink":                 {gosplit.Options{Sink: gosplit.NewMemorySink()}, nil},
+		"sink with out dir":    {gosplit.Options{Sink: gosplit.NewMemorySink(), OutDir: "out"}, gosplit.ErrInvalidSink},
+		"sink with filter":     {gosplit.Options{Sink: gosplit.NewMemorySink(), Filter: "cat"}, gosplit.ErrInvalidSink},
+		"jobs":                 {gosplit.Options{Jobs: 4}, nil},
+		"negative jobs":        {gosplit.Options{Jobs: -1}, gosplit.ErrInvalidJobs},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := gosplit.New(filePath, prefix, tt.opts)
+			if tt.wantErr == nil && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() = %#v, want %#v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && !errors.Is(err, gosplit.ErrGoSplit) {
+				t.Errorf("New() = %#v, want %#v", err, gosplit.ErrGoSplit)
+			}
+		})
+	}
+}
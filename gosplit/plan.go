@@ -0,0 +1,233 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"errors"
+	"io"
+	"sort"
+)
+
+// SplitMode represents the method of a split planned by Plan.
+type SplitMode int
+
+// Split modes.
+const (
+	// SplitLines is the split of ByLines.
+	SplitLines SplitMode = iota
+	// SplitBytes is the split of ByBytes.
+	SplitBytes
+	// SplitLineBytes is the split of ByLineBytes.
+	SplitLineBytes
+	// SplitNumber is the split of ByNumber.
+	SplitNumber
+	// SplitLineNumber is the split of ByLineNumber.
+	SplitLineNumber
+	// SplitRoundRobin is the split of ByRoundRobin.
+	SplitRoundRobin
+)
+
+// Plan describes the output files which a split would create.
+type Plan struct {
+	// InputSize is the size of the input, or -1 if it is unknown.
+	InputSize int64 `json:"input_size"`
+	// Chunks are the output files created before the split ends or fails.
+	Chunks []PlanChunk `json:"chunks"`
+	// SuffixExhausted is true if the split would fail with ErrSuffixExhausted after creating Chunks.
+	SuffixExhausted bool `json:"suffix_exhausted"`
+	// NoFreeSpace is true if the split would fail with ErrNoFreeSpace before creating any output file.
+	NoFreeSpace bool `json:"no_free_space"`
+}
+
+// PlanChunk describes an output file of a split, as the same as ManifestChunk without the hash.
+type PlanChunk struct {
+	// Name is the output file name passed to the sink, i.e. prefix and suffix.
+	Name string `json:"name"`
+	// Index is the number of the output file starting at 0.
+	Index int `json:"index"`
+	// Offset is the byte offset of the output file in the input, or -1 with round robin.
+	Offset int64 `json:"offset"`
+	// Length is the number of bytes of the output file before compression.
+	Length int64 `json:"length"`
+	// FirstLine and LastLine are the line range of the output file starting at 1, including both ends.
+	// LastLine is less than FirstLine if the output file is empty. Both are -1 with round robin,
+	// and with SplitBytes and SplitNumber whose output files are planned without reading the input.
+	FirstLine int64 `json:"first_line"`
+	LastLine  int64 `json:"last_line"`
+}
+
+// Err returns the error which the split would fail with, or nil if it would succeed.
+func (p *Plan) Err() error {
+	switch {
+	case p.NoFreeSpace:
+		return wrapper.Errorf("%w", ErrNoFreeSpace)
+	case p.SuffixExhausted:
+		return wrapper.Errorf("%w: after %d output files", ErrSuffixExhausted, len(p.Chunks))
+	}
+	return nil
+}
+
+// Plan returns the output files which the split of mode by value would create, without writing them.
+//
+// The input is read only as needed: SplitBytes of the input of known size and SplitNumber are planned
+// from the size, and the other modes read the whole input to find the lines. The input from a stream is consumed.
+func (g *GoSplit) Plan(mode SplitMode, value int64) (*Plan, error) {
+	if value <= 0 {
+		switch mode {
+		case SplitLines:
+			return nil, wrapper.Errorf("%w: %#v", ErrInvalidLines, value)
+		case SplitBytes, SplitLineBytes:
+			return nil, wrapper.Errorf("%w: %#v", ErrInvalidBytes, value)
+		default:
+			return nil, wrapper.Errorf("%w: %#v", ErrInvalidNumber, value)
+		}
+	}
+
+	in, gerr := g.openPlanInput(mode)
+	if gerr != nil {
+		return nil, gerr
+	}
+	defer in.Close()
+
+	plan := &Plan{InputSize: in.size}
+	if gerr := g.checkFileSize(in); errors.Is(gerr, ErrNoFreeSpace) {
+		plan.NoFreeSpace = true
+	} else if gerr != nil {
+		return nil, gerr
+	}
+
+	switch {
+	case mode == SplitBytes && in.size >= 0 && !g.bCountCompressed:
+		gerr = g.planByBoundaries(plan, bytesEnds(in.size, value))
+	case mode == SplitNumber:
+		gerr = g.planByBoundaries(plan, numberEnds(in.size, int(value)))
+	default:
+		gerr = g.planBySplitting(plan, in, mode, value)
+	}
+	if gerr != nil {
+		return nil, gerr
+	}
+	return plan, nil
+}
+
+// openPlanInput opens the input as the same as the split of mode.
+func (g *GoSplit) openPlanInput(mode SplitMode) (*input, g.Error) {
+	switch mode {
+	case SplitNumber:
+		return g.openSizedInput(false)
+	case SplitLineNumber:
+		return g.openSizedInput(true)
+	default:
+		return g.openInput()
+	}
+}
+
+// planByBoundaries adds the output files at the end offsets of ends to plan, as the same as doByBoundaries.
+func (g *GoSplit) planByBoundaries(plan *Plan, ends chunkEnds) g.Error {
+	nFiles := 0
+	for i, start := 0, int64(0); i < ends.n; i++ {
+		// the rest of the chunks are empty
+		if g.bElideEmptyFiles && start >= ends.fileSize {
+			break
+		}
+		end, gerr := ends.end(i)
+		if gerr != nil {
+			return gerr
+		}
+		chunkSize := end - start
+		chunkStart := start
+		start = end
+		if g.bElideEmptyFiles && chunkSize == 0 {
+			continue
+		}
+
+		name, gerr := g.generateOutFileName(nFiles)
+		if errors.Is(gerr, ErrSuffixExhausted) {
+			plan.SuffixExhausted = true
+			return nil
+		}
+		if gerr != nil {
+			return gerr
+		}
+
+		plan.Chunks = append(plan.Chunks, PlanChunk{
+			Name:      name,
+			Index:     nFiles,
+			Offset:    chunkStart,
+			Length:    chunkSize,
+			FirstLine: -1,
+			LastLine:  -1,
+		})
+		nFiles++
+	}
+	return nil
+}
+
+// planBySplitting adds the output files to plan by splitting the input of mode by value with the output files
+// discarded, recording them as Manifest.
+func (g *GoSplit) planBySplitting(plan *Plan, in *input, mode SplitMode, value int64) g.Error {
+	sink, filterCommand, wManifest, wVerbose := g.sink, g.filterCommand, g.wManifest, g.wVerbose
+	g.sink, g.filterCommand, g.wManifest, g.wVerbose = discardSink{}, "", io.Discard, io.Discard
+	defer func() {
+		g.sink, g.filterCommand, g.wManifest, g.wVerbose = sink, filterCommand, wManifest, wVerbose
+		g.manifest = nil
+		g.created = nil
+		g.nCompleted.Store(0)
+		g.nDiscarded.Store(0)
+	}()
+
+	var gerr error
+	switch mode {
+	case SplitLines:
+		gerr = g.doByLines(in.r, int(value))
+	case SplitBytes:
+		gerr = g.doByBytes(in.r, in.size, value)
+	case SplitLineBytes:
+		gerr = g.doByLineBytes(in.r, value)
+	case SplitLineNumber:
+		gerr = g.doByLineNumber(in.rs, in.size, int(value))
+	default:
+		gerr = g.doByRoundRobin(in.r, int(value))
+	}
+	if errors.Is(gerr, ErrSuffixExhausted) {
+		plan.SuffixExhausted = true
+	} else if gerr != nil {
+		return wrapper.Errorf("%w", gerr)
+	}
+
+	if g.manifest == nil {
+		return nil
+	}
+	for _, chunk := range g.manifest.chunks {
+		plan.Chunks = append(plan.Chunks, PlanChunk{
+			Name:      chunk.Name,
+			Index:     chunk.Index,
+			Offset:    chunk.Offset,
+			Length:    chunk.Length,
+			FirstLine: chunk.FirstLine,
+			LastLine:  chunk.LastLine,
+		})
+	}
+	sort.Slice(plan.Chunks, func(i, j int) bool {
+		return plan.Chunks[i].Index < plan.Chunks[j].Index
+	})
+	return nil
+}
+
+// discardSink discards the output files.
+type discardSink struct{}
+
+// Create implements Sink.
+func (discardSink) Create(name string) (io.WriteCloser, error) {
+	return nopWriteCloser{io.Discard}, nil
+}
+
+// nopWriteCloser is io.Writer with the no-op Close.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close implements io.Closer.
+func (nopWriteCloser) Close() error {
+	return nil
+}
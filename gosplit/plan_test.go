@@ -0,0 +1,160 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"errors"
+	"testing"
+)
+
+// failingReaderAt fails reading at any offset.
+type failingReaderAt struct{}
+
+func (failingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return 0, errors.New("unexpected read")
+}
+
+func TestGoSplit_Plan(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		mode  gosplit.SplitMode
+		value int64
+		split func(g *gosplit.GoSplit) error
+	}{
+		"ByLines":      {gosplit.SplitLines, 10, func(g *gosplit.GoSplit) error { return g.ByLines(10) }},
+		"ByNumber":     {gosplit.SplitNumber, 4, func(g *gosplit.GoSplit) error { return g.ByNumber(4) }},
+		"ByLineNumber": {gosplit.SplitLineNumber, 4, func(g *gosplit.GoSplit) error { return g.ByLineNumber(4) }},
+		"ByRoundRobin": {gosplit.SplitRoundRobin, 4, func(g *gosplit.GoSplit) error { return g.ByRoundRobin(4) }},
+		"ByBytes":      {gosplit.SplitBytes, 500, func(g *gosplit.GoSplit) error { return g.ByBytes(500) }},
+		"ByLineBytes":  {gosplit.SplitLineBytes, 500, func(g *gosplit.GoSplit) error { return g.ByLineBytes(500) }},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, manifest := helperSplitWithManifest(t, tt.split)
+
+			sink := gosplit.NewMemorySink()
+			g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{Sink: sink})
+			plan, gerr := g.Plan(tt.mode, tt.value)
+			if gerr != nil {
+				t.Fatalf("Plan() = %#v, want nil", gerr)
+			}
+			if names := sink.Names(); len(names) != 0 {
+				t.Errorf("Names() = %#v, want empty", names)
+			}
+			if plan.InputSize != 1455 {
+				t.Errorf("InputSize = %d, want 1455", plan.InputSize)
+			}
+			if err := plan.Err(); err != nil {
+				t.Errorf("Err() = %#v, want nil", err)
+			}
+
+			if len(plan.Chunks) != len(manifest.Chunks) {
+				t.Fatalf("len(Chunks) = %d, want %d", len(plan.Chunks), len(manifest.Chunks))
+			}
+			for i, got := range plan.Chunks {
+				want := manifest.Chunks[i]
+				if got.Name != want.Name || got.Index != want.Index || got.Offset != want.Offset || got.Length != want.Length {
+					t.Errorf("Chunks[%d] = %#v, want %#v", i, got, want)
+				}
+				// the lines are not counted by the modes planned from the size
+				if tt.mode != gosplit.SplitNumber && tt.mode != gosplit.SplitBytes && (got.FirstLine != want.FirstLine || got.LastLine != want.LastLine) {
+					t.Errorf("Chunks[%d] = %#v, want %#v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGoSplit_Plan_SuffixExhausted(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		mode  gosplit.SplitMode
+		value int64
+	}{
+		"ByLines":  {gosplit.SplitLines, 1},
+		"ByNumber": {gosplit.SplitNumber, 20},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := gosplit.Options{Sink: gosplit.NewMemorySink(), Suffix: gosplit.Suffix{Type: gosplit.SuffixNumeric, Length: 1}}
+			g := helperNew(t, "testdata/example.txt", "x", opts)
+			plan, gerr := g.Plan(tt.mode, tt.value)
+			if gerr != nil {
+				t.Fatalf("Plan() = %#v, want nil", gerr)
+			}
+			if !plan.SuffixExhausted || len(plan.Chunks) != 10 {
+				t.Errorf("Plan() has SuffixExhausted %v and %d chunks, want true and 10", plan.SuffixExhausted, len(plan.Chunks))
+			}
+			if err := plan.Err(); !errors.Is(err, gosplit.ErrSuffixExhausted) {
+				t.Errorf("Err() = %#v, want %#v", err, gosplit.ErrSuffixExhausted)
+			}
+		})
+	}
+}
+
+func TestGoSplit_Plan_NoRead(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		mode    gosplit.SplitMode
+		value   int64
+		nChunks int
+	}{
+		"ByNumber": {gosplit.SplitNumber, 4, 4},
+		"ByBytes":  {gosplit.SplitBytes, 300, 4},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			g, err := gosplit.NewReaderAt(failingReaderAt{}, 1000, "x", gosplit.Options{Sink: gosplit.NewMemorySink()})
+			if err != nil {
+				t.Fatal("NewReaderAt() failed:", err)
+			}
+			plan, gerr := g.Plan(tt.mode, tt.value)
+			if gerr != nil {
+				t.Fatalf("Plan() = %#v, want nil", gerr)
+			}
+			if len(plan.Chunks) != tt.nChunks {
+				t.Errorf("len(Chunks) = %d, want %d", len(plan.Chunks), tt.nChunks)
+			}
+		})
+	}
+}
+
+func TestGoSplit_Plan_Invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		mode    gosplit.SplitMode
+		wantErr error
+	}{
+		"lines":  {gosplit.SplitLines, gosplit.ErrInvalidLines},
+		"bytes":  {gosplit.SplitBytes, gosplit.ErrInvalidBytes},
+		"number": {gosplit.SplitNumber, gosplit.ErrInvalidNumber},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{Sink: gosplit.NewMemorySink()})
+			if _, gerr := g.Plan(tt.mode, 0); !errors.Is(gerr, tt.wantErr) {
+				t.Errorf("Plan() = %#v, want %#v", gerr, tt.wantErr)
+			}
+		})
+	}
+}
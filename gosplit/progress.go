@@ -0,0 +1,136 @@
+package gosplit
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressInterval is the minimum interval of reporting Progress while writing output files.
+const progressInterval = 100 * time.Millisecond
+
+// Progress is the progress of a split reported to Options.Progress.
+type Progress struct {
+	// BytesRead is the bytes of the input read and written to the output files,
+	// including the ones skipped by resuming.
+	BytesRead int64
+	// TotalSize is the size of the input, or -1 if it is unknown.
+	TotalSize int64
+	// ChunkIndex is the index of the output file created last, or -1 before the first one.
+	ChunkIndex int
+	// Elapsed is the time since the split started.
+	Elapsed time.Duration
+	// BytesPerSecond is the average throughput of the split, not including the bytes skipped by resuming.
+	BytesPerSecond float64
+	// Done is true for the last report of the split, whether it has succeeded or not.
+	Done bool
+}
+
+// progress reports Progress of the current split to the callback.
+type progress struct {
+	fn         func(Progress)
+	totalSize  int64
+	skipped    int64
+	start      time.Time
+	nRead      atomic.Int64
+	chunkIndex atomic.Int64
+	mu         sync.Mutex // serializes the calls of fn
+	last       time.Time
+}
+
+// startProgress starts reporting Progress of the split of the input of totalSize, if it is enabled.
+// It must be called after startJournal to count the bytes skipped by resuming.
+func (g *GoSplit) startProgress(totalSize int64) {
+	if g.progressFunc == nil {
+		return
+	}
+
+	p := &progress{fn: g.progressFunc, totalSize: totalSize, start: time.Now()}
+	if g.journal != nil {
+		p.skipped = g.journal.end
+	}
+	p.nRead.Store(p.skipped)
+	p.chunkIndex.Store(-1)
+	g.progress = p
+	p.report(true, false)
+}
+
+// finishProgress reports the final Progress of the split, if it is enabled.
+func (g *GoSplit) finishProgress() {
+	p := g.progress
+	g.progress = nil
+	if p == nil {
+		return
+	}
+
+	p.report(true, true)
+}
+
+// report calls the callback with the current Progress at most once per progressInterval unless bForce.
+// The last report is marked with bDone.
+func (p *progress) report(bForce bool, bDone bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if !bForce && now.Sub(p.last) < progressInterval {
+		return
+	}
+	p.last = now
+
+	nRead := p.nRead.Load()
+	elapsed := now.Sub(p.start)
+	bytesPerSecond := 0.0
+	if elapsed > 0 {
+		bytesPerSecond = float64(nRead-p.skipped) / elapsed.Seconds()
+	}
+	p.fn(Progress{
+		BytesRead:      nRead,
+		TotalSize:      p.totalSize,
+		ChunkIndex:     int(p.chunkIndex.Load()),
+		Elapsed:        elapsed,
+		BytesPerSecond: bytesPerSecond,
+		Done:           bDone,
+	})
+}
+
+// progressWriter counts the bytes written to an output file for Progress.
+type progressWriter struct {
+	io.WriteCloser
+	p    *progress
+	size int64
+}
+
+// progressOutFile returns the writer of w counting the bytes of the n-th output file, if Progress is enabled.
+func (g *GoSplit) progressOutFile(w io.WriteCloser, number int) io.WriteCloser {
+	if g.progress == nil {
+		return w
+	}
+
+	g.progress.chunkIndex.Store(int64(number))
+	g.progress.report(false, false)
+	return &progressWriter{WriteCloser: w, p: g.progress}
+}
+
+// Write implements io.Writer.
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.WriteCloser.Write(p)
+	pw.size += int64(n)
+	pw.p.nRead.Add(int64(n))
+	pw.p.report(false, false)
+	return n, err
+}
+
+// Abort implements aborter.
+func (pw *progressWriter) Abort() error {
+	return abortOutFile(pw.WriteCloser)
+}
+
+// outSize implements sizer if the underlying writer does.
+func (pw *progressWriter) outSize() (int64, error) {
+	if s, ok := pw.WriteCloser.(sizer); ok {
+		return s.outSize()
+	}
+	return pw.size, nil
+}
@@ -0,0 +1,66 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"testing"
+)
+
+func TestGoSplit_Progress(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		opts           gosplit.Options
+		split          func(g *gosplit.GoSplit) error
+		wantChunkIndex int
+	}{
+		"ByLines":      {gosplit.Options{}, func(g *gosplit.GoSplit) error { return g.ByLines(10) }, 4},
+		"ByBytes":      {gosplit.Options{}, func(g *gosplit.GoSplit) error { return g.ByBytes(100) }, 14},
+		"ByBytes jobs": {gosplit.Options{Jobs: 4}, func(g *gosplit.GoSplit) error { return g.ByBytes(100) }, 14},
+		"ByNumber":     {gosplit.Options{}, func(g *gosplit.GoSplit) error { return g.ByNumber(4) }, 3},
+		"ByRoundRobin": {gosplit.Options{}, func(g *gosplit.GoSplit) error { return g.ByRoundRobin(4) }, 3},
+		"ByLineBytes":  {gosplit.Options{}, func(g *gosplit.GoSplit) error { return g.ByLineBytes(500) }, 3},
+		"compress":     {gosplit.Options{Compress: gosplit.CompressGzip}, func(g *gosplit.GoSplit) error { return g.ByLines(10) }, 4},
+		"ByLineNumber": {gosplit.Options{}, func(g *gosplit.GoSplit) error { return g.ByLineNumber(2) }, 1},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var reports []gosplit.Progress
+			opts := tt.opts
+			opts.Sink = gosplit.NewMemorySink()
+			opts.Progress = func(p gosplit.Progress) {
+				reports = append(reports, p)
+			}
+			g := helperNew(t, "testdata/example.txt", "x", opts)
+
+			if err := tt.split(g); err != nil {
+				t.Fatalf("split = %#v, want nil", err)
+			}
+
+			if len(reports) < 2 {
+				t.Fatalf("Progress is called %d times, want 2 or more", len(reports))
+			}
+			first, last := reports[0], reports[len(reports)-1]
+			if first.BytesRead != 0 || first.ChunkIndex != -1 {
+				t.Errorf("first Progress = %#v, want BytesRead 0 and ChunkIndex -1", first)
+			}
+			for i, p := range reports {
+				if p.Done != (i == len(reports)-1) {
+					t.Errorf("Progress #%d has Done %v", i, p.Done)
+				}
+			}
+			if last.BytesRead != 1455 || last.TotalSize != 1455 || last.ChunkIndex != tt.wantChunkIndex {
+				t.Errorf("last Progress = %#v, want BytesRead 1455, TotalSize 1455 and ChunkIndex %d", last, tt.wantChunkIndex)
+			}
+			for i := 1; i < len(reports); i++ {
+				if reports[i].BytesRead < reports[i-1].BytesRead {
+					t.Errorf("BytesRead decreases from %d to %d", reports[i-1].BytesRead, reports[i].BytesRead)
+				}
+			}
+		})
+	}
+}
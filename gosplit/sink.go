@@ -0,0 +1,335 @@
+package gosplit
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+// Sink is the destination of output files.
+//
+// The implementations in this package are safe for concurrent use.
+type Sink interface {
+	// Create returns the writer of the output file of name, which is complete when the writer is closed.
+	// If the writer has the method Abort() error, it is called instead of Close to discard the output file
+	// when it is incomplete due to an error.
+	Create(name string) (io.WriteCloser, error)
+}
+
+// DirSink writes output files into the directory, which is the default behavior.
+type DirSink struct {
+	dir string
+}
+
+// NewDirSink returns a new DirSink writing into dir. The current directory is used if dir is empty.
+func NewDirSink(dir string) *DirSink {
+	return &DirSink{dir: dir}
+}
+
+// Path returns the path of the output file of name, which may be an absolute path if the directory is empty.
+func (s *DirSink) Path(name string) string {
+	if s.dir == "" {
+		return name
+	}
+	return path.Join(s.dir, name)
+}
+
+// Create implements Sink.
+//
+// The output file is written to a temporary name in the directory, and renamed to name when it is closed,
+// so that an incomplete output file never appears with name.
+func (s *DirSink) Create(name string) (io.WriteCloser, error) {
+	f, err := createAtomicFile(s.Path(name))
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// TarSink writes output files as the entries of a tar stream.
+//
+// Each entry is spooled into a temporary file in $TMPDIR until it is closed, since a tar header needs its size.
+// Close must be called after splitting to write the end of the archive.
+type TarSink struct {
+	mu sync.Mutex
+	tw *tar.Writer
+}
+
+// NewTarSink returns a new TarSink writing a tar stream to w.
+func NewTarSink(w io.Writer) *TarSink {
+	return &TarSink{tw: tar.NewWriter(w)}
+}
+
+// Create implements Sink.
+func (s *TarSink) Create(name string) (io.WriteCloser, error) {
+	return newSpooledEntry(name, func(e *spooledEntry) error {
+		defer e.remove()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o644,
+			Size:     e.size,
+			ModTime:  time.Now(),
+		}
+		if err := s.tw.WriteHeader(hdr); err != nil {
+			return wrapper.Errorf("failed to write tar header: %w", err)
+		}
+		if _, err := io.Copy(s.tw, e.f); err != nil {
+			return wrapper.Errorf("failed to write: %w", err)
+		}
+		return nil
+	})
+}
+
+// Close writes the end of the tar stream. It does not close the underlying writer.
+func (s *TarSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.tw.Close(); err != nil {
+		return wrapper.Errorf("failed to close: %w", err)
+	}
+	return nil
+}
+
+// ZipSink writes output files as the entries of a zip archive.
+//
+// An entry is streamed into the archive with a data descriptor, which tells its size after the data.
+// Since a zip archive is written one entry at a time, the entries created while another one is being streamed,
+// e.g. the outputs of "-n r/N", are spooled into temporary files in $TMPDIR and written after it is closed.
+// The incomplete entry being streamed cannot be discarded after an error.
+// Close must be called after splitting to write the central directory.
+type ZipSink struct {
+	mu         sync.Mutex
+	zw         *zip.Writer
+	bStreaming bool
+	pending    []*spooledEntry // the entries closed while another one is being streamed
+}
+
+// NewZipSink returns a new ZipSink writing a zip archive to w.
+func NewZipSink(w io.Writer) *ZipSink {
+	return &ZipSink{zw: zip.NewWriter(w)}
+}
+
+// Create implements Sink.
+func (s *ZipSink) Create(name string) (io.WriteCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bStreaming {
+		return newSpooledEntry(name, s.commitSpooled)
+	}
+
+	w, err := s.zw.CreateHeader(zipHeader(name))
+	if err != nil {
+		return nil, wrapper.Errorf("failed to write zip header: %w", err)
+	}
+	s.bStreaming = true
+	return &zipEntry{s: s, w: w}, nil
+}
+
+// zipHeader returns the header of the zip entry of name.
+func zipHeader(name string) *zip.FileHeader {
+	return &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	}
+}
+
+// commitSpooled writes the spooled entry e, or keeps it pending while another entry is being streamed.
+func (s *ZipSink) commitSpooled(e *spooledEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bStreaming {
+		s.pending = append(s.pending, e)
+		return nil
+	}
+	return s.writeSpooled(e)
+}
+
+// writeSpooled writes the spooled entry e into the archive and removes it. s.mu must be held.
+func (s *ZipSink) writeSpooled(e *spooledEntry) error {
+	defer e.remove()
+
+	w, err := s.zw.CreateHeader(zipHeader(e.name))
+	if err != nil {
+		return wrapper.Errorf("failed to write zip header: %w", err)
+	}
+	if _, err := io.Copy(w, e.f); err != nil {
+		return wrapper.Errorf("failed to write: %w", err)
+	}
+	return nil
+}
+
+// flushPending writes the pending entries. s.mu must be held.
+func (s *ZipSink) flushPending() error {
+	pending := s.pending
+	s.pending = nil
+
+	var errFirst error
+	for _, e := range pending {
+		if err := s.writeSpooled(e); err != nil && errFirst == nil {
+			errFirst = err
+		}
+	}
+	return errFirst
+}
+
+// Close writes the pending entries and the central directory of the zip archive.
+// It does not close the underlying writer.
+func (s *ZipSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.flushPending(); err != nil {
+		return err
+	}
+	if err := s.zw.Close(); err != nil {
+		return wrapper.Errorf("failed to close: %w", err)
+	}
+	return nil
+}
+
+// zipEntry streams an output file into the zip archive.
+type zipEntry struct {
+	s *ZipSink
+	w io.Writer
+}
+
+// Write implements io.Writer.
+func (e *zipEntry) Write(p []byte) (int, error) {
+	return e.w.Write(p)
+}
+
+// Close implements io.Closer, writing the entries closed while streaming.
+func (e *zipEntry) Close() error {
+	e.s.mu.Lock()
+	defer e.s.mu.Unlock()
+
+	e.s.bStreaming = false
+	return e.s.flushPending()
+}
+
+// MemorySink keeps output files in memory, e.g. for tests.
+type MemorySink struct {
+	mu    sync.Mutex
+	names []string
+	files map[string][]byte
+}
+
+// NewMemorySink returns a new empty MemorySink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{files: map[string][]byte{}}
+}
+
+// Create implements Sink. The output file is visible after the writer is closed.
+func (s *MemorySink) Create(name string) (io.WriteCloser, error) {
+	return &bufferedEntry{commit: func(data []byte) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, ok := s.files[name]; !ok {
+			s.names = append(s.names, name)
+		}
+		s.files[name] = data
+		return nil
+	}}, nil
+}
+
+// Names returns the names of output files in the order they were closed first.
+func (s *MemorySink) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.names...)
+}
+
+// Bytes returns the content of the output file of name, and whether it exists.
+func (s *MemorySink) Bytes(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.files[name]
+	return data, ok
+}
+
+// spooledEntry spools an output file into a temporary file and commits it on Close.
+type spooledEntry struct {
+	name string
+	f    *os.File
+	size int64
+	// commit writes the content read from f, and removes it by remove
+	commit func(e *spooledEntry) error
+}
+
+// newSpooledEntry returns a new spooledEntry of name with its temporary file in $TMPDIR.
+func newSpooledEntry(name string, commit func(e *spooledEntry) error) (*spooledEntry, error) {
+	f, err := os.CreateTemp("", ".gosplit-entry-")
+	if err != nil {
+		return nil, wrapper.Errorf("failed to create: %w", err)
+	}
+	return &spooledEntry{name: name, f: f, commit: commit}, nil
+}
+
+// Write implements io.Writer.
+func (e *spooledEntry) Write(p []byte) (int, error) {
+	n, err := e.f.Write(p)
+	e.size += int64(n)
+	return n, err
+}
+
+// Close implements io.Closer.
+func (e *spooledEntry) Close() error {
+	if _, err := e.f.Seek(0, io.SeekStart); err != nil {
+		e.remove()
+		return wrapper.Errorf("failed to seek: %w", err)
+	}
+	return e.commit(e)
+}
+
+// Abort implements aborter, removing the temporary file without committing it.
+func (e *spooledEntry) Abort() error {
+	return e.remove()
+}
+
+// remove closes and removes the temporary file.
+func (e *spooledEntry) remove() error {
+	e.f.Close()
+	if err := os.Remove(e.f.Name()); err != nil {
+		return wrapper.Errorf("failed to remove: %w", err)
+	}
+	return nil
+}
+
+// bufferedEntry buffers an output file in memory and commits it on Close.
+type bufferedEntry struct {
+	buf    bytes.Buffer
+	commit func(data []byte) error
+}
+
+// Write implements io.Writer.
+func (e *bufferedEntry) Write(p []byte) (int, error) {
+	return e.buf.Write(p)
+}
+
+// Close implements io.Closer.
+func (e *bufferedEntry) Close() error {
+	return e.commit(e.buf.Bytes())
+}
+
+// Abort implements aborter, discarding the buffer without committing it.
+func (e *bufferedEntry) Abort() error {
+	e.buf = bytes.Buffer{}
+	return nil
+}
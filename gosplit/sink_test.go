@@ -0,0 +1,232 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"testing"
+)
+
+// sinkFiles are the output files of splitting "a\nb\nc\n" by 1 line with prefix "x".
+var sinkFiles = []struct {
+	name    string
+	content string
+}{
+	{"xaa", "a\n"},
+	{"xab", "b\n"},
+	{"xac", "c\n"},
+}
+
+func helperSplitToSink(t *testing.T, sink gosplit.Sink) {
+	t.Helper()
+
+	g := helperNew(t, "testdata/abc.txt", "x", gosplit.Options{Sink: sink})
+	if err := g.ByLines(1); err != nil {
+		t.Fatal("ByLines() failed:", err)
+	}
+}
+
+func TestDirSink(t *testing.T) {
+	t.Parallel()
+
+	outDir := t.TempDir()
+	helperSplitToSink(t, gosplit.NewDirSink(outDir))
+
+	for _, outFile := range sinkFiles {
+		b, err := os.ReadFile(path.Join(outDir, outFile.name))
+		if err != nil {
+			t.Fatal("failed to read:", err)
+		}
+		if got := string(b); got != outFile.content {
+			t.Errorf("content of %#v = %#v, want %#v", outFile.name, got, outFile.content)
+		}
+	}
+}
+
+func TestTarSink(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	sink := gosplit.NewTarSink(&b)
+	helperSplitToSink(t, sink)
+	if err := sink.Close(); err != nil {
+		t.Fatal("Close() failed:", err)
+	}
+
+	tr := tar.NewReader(&b)
+	for _, outFile := range sinkFiles {
+		hdr, err := tr.Next()
+		if err != nil {
+			t.Fatal("failed to read tar header:", err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal("failed to read:", err)
+		}
+		if hdr.Name != outFile.name || string(content) != outFile.content {
+			t.Errorf("entry = %#v %#v, want %#v %#v", hdr.Name, string(content), outFile.name, outFile.content)
+		}
+	}
+	if _, err := tr.Next(); err != io.EOF {
+		t.Errorf("tar stream should end after %d entries: %v", len(sinkFiles), err)
+	}
+}
+
+func TestZipSink(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	sink := gosplit.NewZipSink(&b)
+	helperSplitToSink(t, sink)
+	if err := sink.Close(); err != nil {
+		t.Fatal("Close() failed:", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal("failed to open zip:", err)
+	}
+	if len(zr.File) != len(sinkFiles) {
+		t.Fatalf("len(zr.File) = %d, want %d", len(zr.File), len(sinkFiles))
+	}
+	for i, outFile := range sinkFiles {
+		rc, err := zr.File[i].Open()
+		if err != nil {
+			t.Fatal("failed to open:", err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal("failed to read:", err)
+		}
+		if name := zr.File[i].Name; name != outFile.name || string(content) != outFile.content {
+			t.Errorf("entry = %#v %#v, want %#v %#v", name, string(content), outFile.name, outFile.content)
+		}
+	}
+}
+
+func TestMemorySink(t *testing.T) {
+	t.Parallel()
+
+	sink := gosplit.NewMemorySink()
+	helperSplitToSink(t, sink)
+
+	wantNames := []string{"xaa", "xab", "xac"}
+	if got := sink.Names(); !slices.Equal(got, wantNames) {
+		t.Errorf("Names() = %#v, want %#v", got, wantNames)
+	}
+	for _, outFile := range sinkFiles {
+		content, ok := sink.Bytes(outFile.name)
+		if !ok || string(content) != outFile.content {
+			t.Errorf("Bytes(%#v) = %#v, %v, want %#v, true", outFile.name, string(content), ok, outFile.content)
+		}
+	}
+	if _, ok := sink.Bytes("xad"); ok {
+		t.Errorf("Bytes(%#v) should not exist", "xad")
+	}
+}
+
+func TestMemorySink_RoundRobin(t *testing.T) {
+	t.Parallel()
+
+	sink := gosplit.NewMemorySink()
+	g := helperNew(t, "testdata/abc.txt", "x", gosplit.Options{Sink: sink})
+	if err := g.ByRoundRobin(2); err != nil {
+		t.Fatal("ByRoundRobin() failed:", err)
+	}
+
+	want := map[string]string{"xaa": "a\nc\n", "xab": "b\n"}
+	for name, content := range want {
+		if got, _ := sink.Bytes(name); string(got) != content {
+			t.Errorf("Bytes(%#v) = %#v, want %#v", name, string(got), content)
+		}
+	}
+}
+
+func TestZipSink_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	sink := gosplit.NewZipSink(&b)
+
+	// xaa is streamed, and xab is spooled until xaa is closed
+	ws := make([]io.WriteCloser, 2)
+	for i, name := range []string{"xaa", "xab"} {
+		w, err := sink.Create(name)
+		if err != nil {
+			t.Fatal("Create() failed:", err)
+		}
+		ws[i] = w
+	}
+	for _, line := range []string{"a\n", "b\n", "c\n"} {
+		for _, w := range ws {
+			if _, err := io.WriteString(w, line); err != nil {
+				t.Fatal("Write() failed:", err)
+			}
+		}
+	}
+	for _, i := range []int{1, 0} {
+		if err := ws[i].Close(); err != nil {
+			t.Fatal("Close() failed:", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal("Close() failed:", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal("failed to open zip:", err)
+	}
+	if len(zr.File) != 2 {
+		t.Fatalf("len(zr.File) = %d, want %d", len(zr.File), 2)
+	}
+	for i, name := range []string{"xaa", "xab"} {
+		rc, err := zr.File[i].Open()
+		if err != nil {
+			t.Fatal("failed to open:", err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal("failed to read:", err)
+		}
+		if zr.File[i].Name != name || string(content) != "a\nb\nc\n" {
+			t.Errorf("entry = %#v %#v, want %#v %#v", zr.File[i].Name, string(content), name, "a\nb\nc\n")
+		}
+	}
+}
+
+func TestTarSink_Abort(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	sink := gosplit.NewTarSink(&b)
+	w, err := sink.Create("xaa")
+	if err != nil {
+		t.Fatal("Create() failed:", err)
+	}
+	if _, err := io.WriteString(w, "a\n"); err != nil {
+		t.Fatal("Write() failed:", err)
+	}
+	a, ok := w.(interface{ Abort() error })
+	if !ok {
+		t.Fatal("the writer of TarSink has no Abort()")
+	}
+	if err := a.Abort(); err != nil {
+		t.Fatal("Abort() failed:", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal("Close() failed:", err)
+	}
+
+	if _, err := tar.NewReader(&b).Next(); err != io.EOF {
+		t.Errorf("tar stream should have no entry: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"fmt"
+	"io"
+	"os"
+)
+
+// spoolInput copies the input into a temporary file to know its size and seek it.
+//
+// The temporary file is created in the output directory, or $TMPDIR if it is not given, and removed on Close.
+func (g *GoSplit) spoolInput(in *input) (*input, g.Error) {
+	dir := ""
+	if ds, ok := g.sink.(*DirSink); ok && g.filterCommand == "" {
+		dir = ds.dir
+	}
+
+	f, err := os.CreateTemp(dir, ".gosplit-spool-")
+	if err != nil {
+		return nil, wrapper.Errorf("failed to create: %w", err)
+	}
+	remove := func() error {
+		f.Close()
+		return os.Remove(f.Name())
+	}
+	fmt.Fprintf(g.wVerbose, "spooling input to %#v\n", f.Name())
+
+	size, err := io.Copy(f, in.r)
+	if err != nil {
+		remove()
+		return nil, wrapper.Errorf("failed to spool: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		remove()
+		return nil, wrapper.Errorf("failed to seek: %w", err)
+	}
+
+	return &input{r: f, rs: f, size: size, close: remove}, nil
+}
+
+// hintSize sets the size of the input which is not known from itself to g.inputSize, if it is given.
+// The size must be checked by checkStreamSize after reading.
+func (g *GoSplit) hintSize(in *input) {
+	if g.inputSize <= 0 || in.rs != nil {
+		return
+	}
+
+	in.size = g.inputSize
+	if _, ok := in.r.(*countReader); !ok {
+		in.r = &countReader{r: in.r}
+	}
+}
+
+// countReader counts the bytes read through it.
+type countReader struct {
+	r     io.Reader
+	nRead int64
+}
+
+// Read implements io.Reader.
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.nRead += int64(n)
+	return n, err
+}
+
+// checkStreamSize checks that the size of the input read so far is the same as the size given in advance,
+// i.e. the hint, and that no byte is left.
+func (in *input) checkStreamSize() g.Error {
+	cr, ok := in.r.(*countReader)
+	if !ok || in.size < 0 || in.rs != nil {
+		return nil
+	}
+
+	n, err := io.Copy(io.Discard, cr)
+	if err != nil {
+		return wrapper.Errorf("failed to read: %w", err)
+	}
+	if n > 0 || cr.nRead != in.size {
+		return wrapper.Errorf("%w: %d bytes read, but %d bytes expected", ErrUnknownSize, cr.nRead, in.size)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestOptions_Spool(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		split func(g *gosplit.GoSplit) error
+		want  []string
+	}{
+		"ByNumber":            {func(g *gosplit.GoSplit) error { return g.ByNumber(2) }, []string{"a\nbbb", "\nccc\n"}},
+		"ByLineNumber":        {func(g *gosplit.GoSplit) error { return g.ByLineNumber(2) }, []string{"a\nbbb\n", "ccc\n"}},
+		"ExtractByNumber":     {func(g *gosplit.GoSplit) error { return g.ExtractByNumber(2, 2) }, nil},
+		"ExtractByLineNumber": {func(g *gosplit.GoSplit) error { return g.ExtractByLineNumber(1, 2) }, nil},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			outDir := t.TempDir()
+			var out strings.Builder
+			opts := gosplit.Options{OutDir: outDir, Spool: true, Output: &out}
+			g, gerr := gosplit.NewReader(strings.NewReader("a\nbbb\nccc\n"), "x", opts)
+			if gerr != nil {
+				t.Fatal("NewReader() failed:", gerr)
+			}
+			if err := tt.split(g); err != nil {
+				t.Fatal("split failed:", err)
+			}
+
+			// the spooled file is removed from the output directory
+			entries, err := os.ReadDir(outDir)
+			if err != nil {
+				t.Fatal("failed to read dir:", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("%d files in the output directory, want %d", len(entries), len(tt.want))
+			}
+			for i, want := range tt.want {
+				b, err := os.ReadFile(outDir + "/" + entries[i].Name())
+				if err != nil {
+					t.Fatal("failed to read:", err)
+				}
+				if got := string(b); got != want {
+					t.Errorf("content of %#v = %#v, want %#v", entries[i].Name(), got, want)
+				}
+			}
+			if tt.want == nil && out.Len() == 0 {
+				t.Errorf("nothing extracted")
+			}
+		})
+	}
+}
+
+func TestOptions_InputSize(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		inputSize int64
+		bSpool    bool
+		split     func(g *gosplit.GoSplit) error
+		wantErr   error
+	}{
+		"exact":                  {10, false, func(g *gosplit.GoSplit) error { return g.ByNumber(2) }, nil},
+		"too large":              {11, false, func(g *gosplit.GoSplit) error { return g.ByNumber(2) }, gosplit.ErrUnknownSize},
+		"too small":              {9, false, func(g *gosplit.GoSplit) error { return g.ByNumber(2) }, gosplit.ErrUnknownSize},
+		"seek without spool":     {10, false, func(g *gosplit.GoSplit) error { return g.ByLineNumber(2) }, gosplit.ErrUnknownSize},
+		"seek with spool":        {10, true, func(g *gosplit.GoSplit) error { return g.ByLineNumber(2) }, nil},
+		"no hint without spool":  {0, false, func(g *gosplit.GoSplit) error { return g.ByNumber(2) }, gosplit.ErrUnknownSize},
+		"negative":               {-1, false, nil, gosplit.ErrInvalidBytes},
+		"ignored for byte modes": {1, false, func(g *gosplit.GoSplit) error { return g.ByBytes(4) }, nil},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sink := gosplit.NewMemorySink()
+			opts := gosplit.Options{Sink: sink, Spool: tt.bSpool, InputSize: tt.inputSize}
+			var err error
+			g, gerr := gosplit.NewReader(strings.NewReader("a\nbbb\nccc\n"), "x", opts)
+			if gerr != nil {
+				err = gerr
+			} else {
+				err = tt.split(g)
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %#v, want %#v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				if got := string(helperJoinSink(t, sink)); got != "a\nbbb\nccc\n" {
+					t.Errorf("joined output files = %#v, want %#v", got, "a\nbbb\nccc\n")
+				}
+			}
+		})
+	}
+}
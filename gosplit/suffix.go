@@ -0,0 +1,254 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SuffixType represents the characters used in suffixes.
+type SuffixType int
+
+// Suffix types.
+const (
+	// SuffixAlphabetic uses a-z, which is the default.
+	SuffixAlphabetic SuffixType = iota
+	// SuffixNumeric uses 0-9.
+	SuffixNumeric
+	// SuffixHex uses 0-9 and a-f.
+	SuffixHex
+)
+
+// table returns the characters of SuffixType.
+func (t SuffixType) table() []byte {
+	switch t {
+	case SuffixNumeric:
+		return []byte("0123456789")
+	case SuffixHex:
+		return []byte("0123456789abcdef")
+	default:
+		return []byte("abcdefghijklmnopqrstuvwxyz")
+	}
+}
+
+// Suffix generates the suffixes of output file names.
+//
+// The zero value generates aa, ab, ..., yz, zaaa, zaab, ... as the same as GNU split.
+type Suffix struct {
+	// Type is the characters used in suffixes.
+	Type SuffixType
+	// Length is the length of suffixes. The suffixes are widened automatically when Length is 0.
+	Length int
+	// Start is the number of the first suffix, e.g. 1 -> 01 with SuffixNumeric.
+	// The suffixes are not widened when Start is specified.
+	Start int
+	// Additional is appended to every suffix, e.g. ".csv".
+	Additional string
+}
+
+// ParseStart converts strStart to the number of the first suffix in the base of Type, e.g. "10" -> 16 with SuffixHex.
+func (s Suffix) ParseStart(strStart string) (int, error) {
+	base := len(s.Type.table())
+	start, err := strconv.ParseInt(strStart, base, 0)
+	if err != nil || start < 0 {
+		return 0, wrapper.Errorf("%w: %#v", ErrInvalidSuffixStart, strStart)
+	}
+	return int(start), nil
+}
+
+// Validate checks that the fields of Suffix are valid.
+func (s Suffix) Validate() error {
+	return s.validate()
+}
+
+// validate is the same as Validate, returning g.Error for the callers in this package.
+func (s Suffix) validate() g.Error {
+	switch s.Type {
+	case SuffixAlphabetic, SuffixNumeric, SuffixHex:
+	default:
+		return wrapper.Errorf("%w: unknown suffix type %#v", ErrInvalidSuffix, s.Type)
+	}
+	if s.Length < 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidSuffixLength, s.Length)
+	}
+	if s.Start < 0 {
+		return wrapper.Errorf("%w: %#v", ErrInvalidSuffixStart, s.Start)
+	}
+	if strings.ContainsAny(s.Additional, `/\`) {
+		return wrapper.Errorf("%w: %#v: contains directory separator", ErrInvalidSuffix, s.Additional)
+	}
+
+	return nil
+}
+
+// Generate returns the n-th suffix including the additional suffix.
+func (s Suffix) Generate(number int) (string, error) {
+	return s.generate(number)
+}
+
+// generate is the same as Generate, returning g.Error for the callers in this package.
+func (s Suffix) generate(number int) (string, g.Error) {
+	if err := s.validate(); err != nil {
+		return "", err
+	}
+
+	table := s.Type.table()
+	length := s.Length
+	if length == 0 && s.Start > 0 {
+		length = max(2, len(strconv.FormatInt(int64(s.Start), len(table))))
+	}
+
+	var suffix []byte
+	var gerr g.Error
+	if length == 0 {
+		suffix = widenedSuffix(table, number)
+	} else {
+		if number > math.MaxInt-s.Start {
+			return "", wrapper.Errorf("%w", ErrSuffixExhausted)
+		}
+		suffix, gerr = fixedSuffix(table, number+s.Start, length)
+		if gerr != nil {
+			return "", gerr
+		}
+	}
+
+	return string(suffix) + s.Additional, nil
+}
+
+// Parse returns the number of suffix generated by Generate, which includes the additional suffix.
+//
+// ErrInvalidSuffix is returned if suffix is not generated by s.
+func (s Suffix) Parse(suffix string) (int, error) {
+	if err := s.validate(); err != nil {
+		return 0, err
+	}
+
+	body, ok := strings.CutSuffix(suffix, s.Additional)
+	if !ok || body == "" {
+		return 0, wrapper.Errorf("%w: %#v", ErrInvalidSuffix, suffix)
+	}
+
+	table := s.Type.table()
+	length := s.Length
+	if length == 0 && s.Start > 0 {
+		length = max(2, len(strconv.FormatInt(int64(s.Start), len(table))))
+	}
+
+	var number int
+	if length == 0 {
+		number, ok = parseWidenedSuffix(table, body)
+	} else {
+		number, ok = parseFixedSuffix(table, body, length)
+		number -= s.Start
+	}
+
+	// check by generating again, which also rejects the wider suffixes in the wrong form such as "za"
+	if !ok || number < 0 {
+		return 0, wrapper.Errorf("%w: %#v", ErrInvalidSuffix, suffix)
+	}
+	if generated, gerr := s.Generate(number); gerr != nil || generated != suffix {
+		return 0, wrapper.Errorf("%w: %#v", ErrInvalidSuffix, suffix)
+	}
+
+	return number, nil
+}
+
+// fixedSuffix returns the n-th suffix of length.
+func fixedSuffix(table []byte, number int, length int) ([]byte, g.Error) {
+	base := len(table)
+
+	suffix := make([]byte, length)
+	for i := len(suffix) - 1; i >= 0; i-- {
+		suffix[i] = table[number%base]
+		number /= base
+	}
+	if number > 0 {
+		return nil, wrapper.Errorf("%w", ErrSuffixExhausted)
+	}
+
+	return suffix, nil
+}
+
+// parseFixedSuffix returns the number of the suffix of length, and whether it is valid.
+func parseFixedSuffix(table []byte, suffix string, length int) (int, bool) {
+	if len(suffix) != length {
+		return 0, false
+	}
+	return parseDigits(table, suffix)
+}
+
+// parseDigits returns the number represented by the characters of table, and whether it is valid.
+func parseDigits(table []byte, digits string) (int, bool) {
+	base := len(table)
+
+	number := 0
+	for i := 0; i < len(digits); i++ {
+		d := bytes.IndexByte(table, digits[i])
+		if d < 0 || number > (math.MaxInt-d)/base {
+			return 0, false
+		}
+		number = number*base + d
+	}
+	return number, true
+}
+
+// widenedSuffix returns the n-th suffix widened automatically as the same as GNU split;
+// aa, ab, ..., yz, zaaa, zaab, ..., zyzz, zzaaaa, and so on.
+func widenedSuffix(table []byte, number int) []byte {
+	base := len(table)
+
+	// the suffixes of the s-th width have s leading last characters followed by s+2 characters
+	// not starting with the last character, then they are sorted in order
+	s := 0
+	count := (base - 1) * base
+	for number >= count {
+		number -= count
+		s++
+		if count > math.MaxInt/base {
+			// number is always less than the next count
+			break
+		}
+		count *= base
+	}
+
+	suffix := make([]byte, 2*s+2)
+	for i := len(suffix) - 1; i >= 0; i-- {
+		if i < s {
+			suffix[i] = table[base-1]
+			continue
+		}
+		suffix[i] = table[number%base]
+		number /= base
+	}
+
+	return suffix
+}
+
+// parseWidenedSuffix returns the number of the suffix widened automatically, and whether it is valid.
+func parseWidenedSuffix(table []byte, suffix string) (int, bool) {
+	base := len(table)
+	if len(suffix)%2 != 0 {
+		return 0, false
+	}
+
+	// skip the counts of the narrower widths, see widenedSuffix
+	s := len(suffix)/2 - 1
+	number := 0
+	count := (base - 1) * base
+	for i := 0; i < s; i++ {
+		if number > math.MaxInt-count || count > math.MaxInt/base {
+			return 0, false
+		}
+		number += count
+		count *= base
+	}
+
+	rest, ok := parseDigits(table, suffix[s:])
+	if !ok || number > math.MaxInt-rest {
+		return 0, false
+	}
+	return number + rest, true
+}
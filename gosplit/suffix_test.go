@@ -0,0 +1,135 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"testing"
+)
+
+func TestSuffix_Generate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		suffix    gosplit.Suffix
+		in        int
+		want      string
+		expectErr bool
+	}{
+		"alphabetic first":      {gosplit.Suffix{}, 0, "aa", false},
+		"alphabetic last":       {gosplit.Suffix{}, 649, "yz", false},
+		"alphabetic widened":    {gosplit.Suffix{}, 650, "zaaa", false},
+		"alphabetic widened2":   {gosplit.Suffix{}, 650 + 16900, "zzaaaa", false},
+		"numeric widened":       {gosplit.Suffix{Type: gosplit.SuffixNumeric}, 90, "9000", false},
+		"hex":                   {gosplit.Suffix{Type: gosplit.SuffixHex}, 239, "ef", false},
+		"hex widened":           {gosplit.Suffix{Type: gosplit.SuffixHex}, 240, "f000", false},
+		"length":                {gosplit.Suffix{Length: 3}, 1, "aab", false},
+		"length exhausted":      {gosplit.Suffix{Length: 1}, 26, "", true},
+		"start":                 {gosplit.Suffix{Type: gosplit.SuffixNumeric, Start: 1}, 0, "01", false},
+		"start not widened":     {gosplit.Suffix{Type: gosplit.SuffixNumeric, Start: 1}, 99, "", true},
+		"start length":          {gosplit.Suffix{Type: gosplit.SuffixNumeric, Start: 123}, 0, "123", false},
+		"start with length":     {gosplit.Suffix{Type: gosplit.SuffixHex, Start: 16, Length: 4}, 1, "0011", false},
+		"additional":            {gosplit.Suffix{Additional: ".csv"}, 1, "ab.csv", false},
+		"additional with slash": {gosplit.Suffix{Additional: "/csv"}, 0, "", true},
+		"negative length":       {gosplit.Suffix{Length: -1}, 0, "", true},
+		"negative start":        {gosplit.Suffix{Start: -1}, 0, "", true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.suffix.Generate(tt.in)
+			if tt.expectErr && err == nil {
+				t.Fatal("want err")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			if tt.want != got {
+				t.Errorf("Generate(%#v) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuffix_ParseStart(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		suffixType gosplit.SuffixType
+		in         string
+		want       int
+		expectErr  bool
+	}{
+		"numeric":    {gosplit.SuffixNumeric, "10", 10, false},
+		"hex":        {gosplit.SuffixHex, "10", 16, false},
+		"hex letter": {gosplit.SuffixHex, "ff", 255, false},
+		"not hex":    {gosplit.SuffixNumeric, "ff", 0, true},
+		"negative":   {gosplit.SuffixNumeric, "-1", 0, true},
+		"empty":      {gosplit.SuffixNumeric, "", 0, true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			suffix := gosplit.Suffix{Type: tt.suffixType}
+			got, err := suffix.ParseStart(tt.in)
+			if tt.expectErr && err == nil {
+				t.Fatal("want err")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			if tt.want != got {
+				t.Errorf("ParseStart(%#v) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuffix_Parse(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		suffix    gosplit.Suffix
+		in        string
+		want      int
+		expectErr bool
+	}{
+		"alphabetic first":    {gosplit.Suffix{}, "aa", 0, false},
+		"alphabetic last":     {gosplit.Suffix{}, "yz", 649, false},
+		"alphabetic widened":  {gosplit.Suffix{}, "zaaa", 650, false},
+		"alphabetic widened2": {gosplit.Suffix{}, "zzaaaa", 650 + 16900, false},
+		"numeric widened":     {gosplit.Suffix{Type: gosplit.SuffixNumeric}, "9000", 90, false},
+		"hex":                 {gosplit.Suffix{Type: gosplit.SuffixHex}, "ef", 239, false},
+		"length":              {gosplit.Suffix{Length: 3}, "aab", 1, false},
+		"start":               {gosplit.Suffix{Type: gosplit.SuffixNumeric, Start: 1}, "01", 0, false},
+		"additional":          {gosplit.Suffix{Additional: ".csv"}, "ab.csv", 1, false},
+		"not widened form":    {gosplit.Suffix{}, "zz", 0, true},
+		"odd length":          {gosplit.Suffix{}, "aaa", 0, true},
+		"wrong length":        {gosplit.Suffix{Length: 3}, "aa", 0, true},
+		"before start":        {gosplit.Suffix{Type: gosplit.SuffixNumeric, Start: 1}, "00", 0, true},
+		"wrong characters":    {gosplit.Suffix{Type: gosplit.SuffixNumeric}, "ab", 0, true},
+		"no additional":       {gosplit.Suffix{Additional: ".csv"}, "ab", 0, true},
+		"empty":               {gosplit.Suffix{}, "", 0, true},
+		"overflow":            {gosplit.Suffix{Type: gosplit.SuffixNumeric, Length: 30}, "999999999999999999999999999999", 0, true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.suffix.Parse(tt.in)
+			if tt.expectErr && err == nil {
+				t.Fatal("want err")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			if tt.want != got {
+				t.Errorf("Parse(%#v) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
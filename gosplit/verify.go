@@ -0,0 +1,232 @@
+package gosplit
+
+import (
+	g "inaz2/GoSplit/internal/gerrors"
+
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// ReadManifest decodes the JSON Manifest from r.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	var manifest Manifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, wrapper.Errorf("failed to read manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// Verify checks the output files of prefix against manifest, re-hashing each of them.
+//
+// All problems are reported in the returned error, which wraps ErrMissingChunk, ErrTruncatedChunk,
+// ErrModifiedChunk and ErrUnexpectedChunk for each output file. The other files starting with prefix
+// are unexpected.
+func Verify(manifest *Manifest, prefix string, opts Options) error {
+	gs, gerr := newGoSplit(prefix, opts)
+	if gerr != nil {
+		return gerr
+	}
+
+	files, gerr := gs.findOutFiles()
+	if gerr != nil {
+		return gerr
+	}
+	found := map[string]outFile{}
+	for _, file := range files {
+		found[file.name] = file
+	}
+
+	var errs []error
+	for _, chunk := range manifest.Chunks {
+		file, ok := found[chunk.Name]
+		delete(found, chunk.Name)
+		if !ok || !file.bRegular {
+			errs = append(errs, fmt.Errorf("%w: %#v", ErrMissingChunk, chunk.Name))
+			continue
+		}
+
+		fmt.Fprintf(gs.wVerbose, "verifying file %#v\n", file.path)
+		size, sum, gerr := gs.hashOutFile(file.path)
+		if isChunkError(gerr) {
+			errs = append(errs, gerr)
+			continue
+		}
+		if gerr != nil {
+			return gerr
+		}
+		switch {
+		case size < chunk.Length:
+			errs = append(errs, fmt.Errorf("%w: %#v: %d of %d bytes", ErrTruncatedChunk, file.path, size, chunk.Length))
+		case size > chunk.Length || sum != chunk.SHA256:
+			errs = append(errs, fmt.Errorf("%w: %#v", ErrModifiedChunk, file.path))
+		}
+	}
+
+	errs = append(errs, unexpectedErrors(found)...)
+	if len(errs) > 0 {
+		return wrapper.Errorf("%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// VerifySource checks that the output files of prefix reproduce the file of filePath when concatenated,
+// using the same suffix options as the split.
+//
+// All problems are reported as the same as Verify. As the boundaries of the output files are not known,
+// a modification shifting the content is reported as ErrModifiedChunk of the output file where it is found,
+// and the output files missing at the end are reported as ErrTruncatedChunk of the last one.
+// The output files after a missing or modified one are not compared.
+func VerifySource(filePath string, prefix string, opts Options) error {
+	gs, gerr := newGoSplit(prefix, opts)
+	if gerr != nil {
+		return gerr
+	}
+
+	files, gerr := gs.findOutFiles()
+	if gerr != nil {
+		return gerr
+	}
+	numbered, unexpected := gs.numberOutFiles(files)
+
+	fSource, err := os.Open(filePath)
+	if err != nil {
+		return wrapper.Errorf("failed to open: %w", err)
+	}
+	defer fSource.Close()
+
+	// the output files are numbered from 0 without gaps up to the last one found
+	nFiles := 1
+	for number := range numbered {
+		nFiles = max(nFiles, number+1)
+	}
+
+	var errs []error
+	var last outFile
+	bGap := false
+	for number := 0; number < nFiles; number++ {
+		file, ok := numbered[number]
+		if !ok {
+			missing, gerr := gs.generateOutFilePath(number)
+			if gerr != nil {
+				return gerr
+			}
+			errs = append(errs, fmt.Errorf("%w: %#v", ErrMissingChunk, missing))
+			bGap = true
+			continue
+		}
+		if bGap {
+			continue
+		}
+
+		fmt.Fprintf(gs.wVerbose, "verifying file %#v\n", file.path)
+		bSame, gerr := gs.compareOutFile(file.path, fSource)
+		if isChunkError(gerr) {
+			errs = append(errs, gerr)
+			bGap = true
+			continue
+		}
+		if gerr != nil {
+			return gerr
+		}
+		if !bSame {
+			errs = append(errs, fmt.Errorf("%w: %#v", ErrModifiedChunk, file.path))
+			bGap = true
+		}
+		last = file
+	}
+
+	// the rest of the input is lost at the end of the last output file
+	if !bGap {
+		n, err := io.Copy(io.Discard, fSource)
+		if err != nil {
+			return wrapper.Errorf("failed to read: %w", err)
+		}
+		if n > 0 {
+			errs = append(errs, fmt.Errorf("%w: %#v: %d bytes short", ErrTruncatedChunk, last.path, n))
+		}
+	}
+
+	found := map[string]outFile{}
+	for _, file := range unexpected {
+		found[file.name] = file
+	}
+	errs = append(errs, unexpectedErrors(found)...)
+	if len(errs) > 0 {
+		return wrapper.Errorf("%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// unexpectedErrors returns the errors of the unexpected files in the order of their names.
+func unexpectedErrors(files map[string]outFile) []error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]error, 0, len(names))
+	for _, name := range names {
+		errs = append(errs, fmt.Errorf("%w: %#v", ErrUnexpectedChunk, files[name].path))
+	}
+	return errs
+}
+
+// isChunkError reports whether err is the problem of an output file, such as the failure of decompression.
+func isChunkError(err error) bool {
+	return errors.Is(err, ErrTruncatedChunk) || errors.Is(err, ErrModifiedChunk)
+}
+
+// hashOutFile returns the size and the hex-encoded SHA-256 hash of the output file of filePath,
+// decompressing it if needed.
+func (g *GoSplit) hashOutFile(filePath string) (int64, string, g.Error) {
+	f, gerr := g.openOutFile(filePath)
+	if gerr != nil {
+		return 0, "", gerr
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", wrapper.Errorf("%w", err)
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// compareOutFile returns whether the content of the output file of filePath is the same as the next bytes from r,
+// decompressing it if needed.
+func (g *GoSplit) compareOutFile(filePath string, r io.Reader) (bool, g.Error) {
+	f, gerr := g.openOutFile(filePath)
+	if gerr != nil {
+		return false, gerr
+	}
+	defer f.Close()
+
+	bufFile := make([]byte, 32*1024)
+	bufSource := make([]byte, len(bufFile))
+	for {
+		n, err := io.ReadFull(f, bufFile)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return false, wrapper.Errorf("%w", err)
+		}
+		if n == 0 {
+			return true, nil
+		}
+
+		m, errSource := io.ReadFull(r, bufSource[:n])
+		if errSource != nil && errSource != io.EOF && errSource != io.ErrUnexpectedEOF {
+			return false, wrapper.Errorf("failed to read: %w", errSource)
+		}
+		if m < n || !bytes.Equal(bufFile[:n], bufSource[:n]) {
+			return false, nil
+		}
+	}
+}
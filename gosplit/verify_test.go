@@ -0,0 +1,136 @@
+package gosplit_test
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"bytes"
+	"errors"
+	"os"
+	"path"
+	"testing"
+)
+
+func helperSplitWithManifestToDir(t *testing.T) (string, *gosplit.Manifest) {
+	t.Helper()
+
+	outDir := t.TempDir()
+	var b bytes.Buffer
+	g := helperNew(t, "testdata/example.txt", "x", gosplit.Options{OutDir: outDir, Manifest: &b})
+	if err := g.ByBytes(300); err != nil {
+		t.Fatal("ByBytes() failed:", err)
+	}
+
+	manifest, err := gosplit.ReadManifest(&b)
+	if err != nil {
+		t.Fatal("ReadManifest() failed:", err)
+	}
+	return outDir, manifest
+}
+
+// verifyCases are the modifications of the output files "xaa" to "xae" of 300 bytes except the last.
+var verifyCases = map[string]struct {
+	modify   func(outDir string) error
+	wantErrs []error
+}{
+	"ok": {
+		func(outDir string) error { return nil },
+		nil,
+	},
+	"missing": {
+		func(outDir string) error { return os.Remove(path.Join(outDir, "xab")) },
+		[]error{gosplit.ErrMissingChunk},
+	},
+	"truncated": {
+		func(outDir string) error { return os.Truncate(path.Join(outDir, "xae"), 10) },
+		[]error{gosplit.ErrTruncatedChunk},
+	},
+	"modified": {
+		func(outDir string) error {
+			return os.WriteFile(path.Join(outDir, "xaa"), bytes.Repeat([]byte{'x'}, 300), 0o644)
+		},
+		[]error{gosplit.ErrModifiedChunk},
+	},
+	"unexpected": {
+		func(outDir string) error { return os.WriteFile(path.Join(outDir, "xaa.orig"), nil, 0o644) },
+		[]error{gosplit.ErrUnexpectedChunk},
+	},
+	"all": {
+		func(outDir string) error {
+			return errors.Join(
+				os.WriteFile(path.Join(outDir, "xaa"), bytes.Repeat([]byte{'x'}, 300), 0o644),
+				os.Remove(path.Join(outDir, "xac")),
+				os.WriteFile(path.Join(outDir, "xzz"), nil, 0o644),
+			)
+		},
+		[]error{gosplit.ErrModifiedChunk, gosplit.ErrMissingChunk, gosplit.ErrUnexpectedChunk},
+	},
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range verifyCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			outDir, manifest := helperSplitWithManifestToDir(t)
+			if err := tt.modify(outDir); err != nil {
+				t.Fatal("failed to modify:", err)
+			}
+
+			err := gosplit.Verify(manifest, "x", gosplit.Options{OutDir: outDir})
+			if tt.wantErrs == nil && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			for _, wantErr := range tt.wantErrs {
+				if !errors.Is(err, wantErr) {
+					t.Errorf("Verify() = %v, want %v", err, wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifySource(t *testing.T) {
+	t.Parallel()
+
+	for name, tt := range verifyCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			outDir, _ := helperSplitWithManifestToDir(t)
+			if err := tt.modify(outDir); err != nil {
+				t.Fatal("failed to modify:", err)
+			}
+
+			err := gosplit.VerifySource("testdata/example.txt", "x", gosplit.Options{OutDir: outDir})
+			if tt.wantErrs == nil && err != nil {
+				t.Fatal("not want err:", err)
+			}
+			for _, wantErr := range tt.wantErrs {
+				if !errors.Is(err, wantErr) {
+					t.Errorf("VerifySource() = %v, want %v", err, wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestVerify_DistinctErrors(t *testing.T) {
+	t.Parallel()
+
+	outDir, manifest := helperSplitWithManifestToDir(t)
+	if err := os.Remove(path.Join(outDir, "xab")); err != nil {
+		t.Fatal("failed to remove:", err)
+	}
+
+	err := gosplit.Verify(manifest, "x", gosplit.Options{OutDir: outDir})
+	if !errors.Is(err, gosplit.ErrGoSplit) {
+		t.Errorf("Verify() = %v, want %v", err, gosplit.ErrGoSplit)
+	}
+	for _, otherErr := range []error{gosplit.ErrTruncatedChunk, gosplit.ErrModifiedChunk, gosplit.ErrUnexpectedChunk} {
+		if errors.Is(err, otherErr) {
+			t.Errorf("Verify() = %v, not want %v", err, otherErr)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// lazyFile creates the file on the first write or close,
+// so that the output is not left behind when the pieces are invalid.
+type lazyFile struct {
+	filePath string
+	f        *os.File
+}
+
+// open creates the file if not yet.
+func (l *lazyFile) open() error {
+	if l.f != nil {
+		return nil
+	}
+
+	f, err := os.Create(l.filePath)
+	if err != nil {
+		return err
+	}
+	l.f = f
+	return nil
+}
+
+// Write implements io.Writer.
+func (l *lazyFile) Write(p []byte) (int, error) {
+	if err := l.open(); err != nil {
+		return 0, err
+	}
+	return l.f.Write(p)
+}
+
+// Close implements io.Closer.
+func (l *lazyFile) Close() error {
+	if err := l.open(); err != nil {
+		return err
+	}
+	return l.f.Close()
+}
+
+// runJoin runs the join subcommand with args, which concatenates the pieces of PREFIX in order.
+func runJoin(args []string) {
+	var (
+		bHelp       bool
+		bVerbose    bool
+		suffix      gosplit.Suffix
+		strCompress string
+		prefix      = "x"
+		filePath    = "-"
+	)
+
+	fs := flag.NewFlagSet("join", flag.ExitOnError)
+	fs.BoolVar(&bHelp, "help", false, "display this help and exit")
+	addSuffixFlags(fs, &suffix)
+	fs.StringVar(&strCompress, "compress", "", "decompress the pieces compressed with FORMAT by the split")
+	fs.BoolVar(&bVerbose, "verbose", false, "print a diagnostic just before each piece is read")
+	fs.Parse(args)
+
+	if bHelp {
+		usageFormat := `Usage: %s join [OPTION]... [PREFIX [FILE]]
+Concatenate PREFIXaa, PREFIXab, ... to FILE in the order of their suffixes;
+default PREFIX is 'x'. Give the same suffix options as the split.
+
+With no FILE, or when FILE is -, write standard output.
+Fail if a piece is missing or an unexpected file starts with PREFIX.
+
+`
+		fmt.Printf(usageFormat, os.Args[0])
+		fs.PrintDefaults()
+		os.Exit(0)
+	}
+
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		prefix = fs.Args()[0]
+	default:
+		prefix = fs.Args()[0]
+		filePath = fs.Args()[1]
+	}
+
+	compression, err := gosplit.ParseCompression(strCompress)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		log.Fatalf("%+v", err)
+	}
+
+	opts := gosplit.Options{Suffix: suffix, Compress: compression}
+	if bVerbose {
+		opts.Verbose = os.Stderr
+	}
+
+	var w io.WriteCloser = os.Stdout
+	if filePath != "-" {
+		w = &lazyFile{filePath: filePath}
+	}
+
+	if err := gosplit.Join(w, prefix, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		log.Fatalf("%+v", err)
+	}
+	if err := w.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		log.Fatalf("%+v", err)
+	}
+}
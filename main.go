@@ -1,35 +1,164 @@
 package main
 
 import (
-	"inaz2/GoSplit/internal/gosplit"
+	"inaz2/GoSplit/gosplit"
 
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 var (
 	bHelp            bool
 	bVersion         bool
 	nLines           int
-	nNumber          int
+	strChunks        string
 	strSize          string
-	bNumericSuffix   bool
+	strLineBytes     string
+	suffix           gosplit.Suffix
 	bElideEmptyFiles bool
+	strFilter        string
+	strArchive       string
+	strManifest      string
+	strCompress      string
+	nCompressLevel   int
+	bCountCompressed bool
+	bDecompress      bool
+	nJobs            int
+	bKeepPartial     bool
+	bResume          bool
+	bSpool           bool
+	strInputSize     string
+	strSeparator     string
 	bVerbose         bool
+	bProgress        bool
+	dryRun           dryRunFlag
 )
 
+// suffixFlag implements flag.Value for the options of the suffix type with an optional FROM,
+// e.g. "-numeric-suffixes" and "-numeric-suffixes=1".
+type suffixFlag struct {
+	suffix     *gosplit.Suffix
+	suffixType gosplit.SuffixType
+	bStart     bool
+}
+
+// String implements flag.Value.
+func (f *suffixFlag) String() string {
+	return ""
+}
+
+// IsBoolFlag makes the value optional as the same as boolean flags.
+func (f *suffixFlag) IsBoolFlag() bool {
+	return true
+}
+
+// Set implements flag.Value.
+func (f *suffixFlag) Set(value string) error {
+	if b, err := strconv.ParseBool(value); err == nil {
+		if b {
+			f.suffix.Type = f.suffixType
+		} else if f.suffix.Type == f.suffixType {
+			f.suffix.Type = gosplit.SuffixAlphabetic
+		}
+		return nil
+	}
+
+	if !f.bStart {
+		return fmt.Errorf("unexpected value %#v", value)
+	}
+
+	suffix := gosplit.Suffix{Type: f.suffixType}
+	start, err := suffix.ParseStart(value)
+	if err != nil {
+		return err
+	}
+	f.suffix.Type = f.suffixType
+	f.suffix.Start = start
+	return nil
+}
+
+// openArchive sets the sink of opts writing the archive file of archivePath, which is created on the first write,
+// so that it is not left behind when the options are invalid.
+//
+// The returned functions finish the archive and close the file, or remove the file after a failed split.
+func openArchive(archivePath string, opts *gosplit.Options) (func() error, func()) {
+	f := &lazyFile{filePath: archivePath}
+	discard := func() {
+		if f.f == nil {
+			return
+		}
+		// the archive may be written to a device or a pipe, e.g. /dev/stdout, which must be kept
+		fileInfo, err := f.f.Stat()
+		f.f.Close()
+		if err == nil && fileInfo.Mode().IsRegular() {
+			os.Remove(archivePath)
+		}
+	}
+
+	if strings.EqualFold(filepath.Ext(archivePath), ".zip") {
+		sink := gosplit.NewZipSink(f)
+		opts.Sink = sink
+		return func() error { return closeBoth(sink, f) }, discard
+	}
+
+	sink := gosplit.NewTarSink(f)
+	opts.Sink = sink
+	return func() error { return closeBoth(sink, f) }, discard
+}
+
+// closeBoth closes the archive sink and then its file, returning the first error.
+func closeBoth(sink io.Closer, f io.Closer) error {
+	errSink := sink.Close()
+	errFile := f.Close()
+	if errSink != nil {
+		return errSink
+	}
+	return errFile
+}
+
+// addSuffixFlags defines the options of the suffix in fs, which are shared with the join subcommand.
+func addSuffixFlags(fs *flag.FlagSet, suffix *gosplit.Suffix) {
+	fs.IntVar(&suffix.Length, "a", 0, "generate suffixes of length N (default 2, widened automatically)")
+	fs.IntVar(&suffix.Length, "suffix-length", 0, "same as -a")
+	fs.StringVar(&suffix.Additional, "additional-suffix", "", "append an additional SUFFIX to file names")
+	fs.Var(&suffixFlag{suffix, gosplit.SuffixNumeric, false}, "d", "use numeric suffixes starting at 0, not alphabetic")
+	fs.Var(&suffixFlag{suffix, gosplit.SuffixNumeric, true}, "numeric-suffixes", "same as -d, but allow setting the start value with =FROM")
+	fs.Var(&suffixFlag{suffix, gosplit.SuffixHex, false}, "x", "use hex suffixes starting at 0, not alphabetic")
+	fs.Var(&suffixFlag{suffix, gosplit.SuffixHex, true}, "hex-suffixes", "same as -x, but allow setting the start value with =FROM")
+}
+
 func init() {
 	flag.BoolVar(&bHelp, "help", false, "display this help and exit")
 	flag.BoolVar(&bVersion, "version", false, "output version information and exit")
 	flag.IntVar(&nLines, "l", 0, "put NUMBER lines/records per output file")
-	flag.IntVar(&nNumber, "n", 0, "split into N files based on size of input")
+	flag.StringVar(&strChunks, "n", "", "generate CHUNKS output files; see explanation below")
 	flag.StringVar(&strSize, "b", "", "put SIZE bytes per output file")
-	flag.BoolVar(&bNumericSuffix, "d", false, "use numeric suffixes starting at 0, not alphabetic")
+	flag.StringVar(&strLineBytes, "C", "", "put at most SIZE bytes of records per output file")
+	flag.StringVar(&strLineBytes, "line-bytes", "", "same as -C")
+	addSuffixFlags(flag.CommandLine, &suffix)
 	flag.BoolVar(&bElideEmptyFiles, "e", false, "do not generate empty output files with '-n'")
+	flag.StringVar(&strSeparator, "t", "", "use SEP instead of newline as the record separator; '\\0' (zero) specifies the NUL character")
+	flag.StringVar(&strFilter, "filter", "", "write to shell COMMAND; file name is $FILE")
+	flag.StringVar(&strArchive, "archive", "", "write output files into the tar archive FILE, or zip if FILE ends with '.zip'")
+	flag.StringVar(&strCompress, "compress", "", "compress each output file with FORMAT (gzip, zlib or flate), appending its extension")
+	flag.IntVar(&nCompressLevel, "compress-level", 0, "use compression LEVEL from 1 (fastest) to 9 (best), or -2 for Huffman only")
+	flag.BoolVar(&bCountCompressed, "count-compressed", false, "count the compressed bytes for SIZE of '-b' and '-C'")
+	flag.BoolVar(&bDecompress, "decompress", false, "decompress the input compressed with gzip, bzip2 or zlib")
+	flag.IntVar(&nJobs, "jobs", 0, "write N output files concurrently with '-b' and '-n' from a regular file")
+	flag.BoolVar(&bKeepPartial, "keep-partial", false, "do not remove the output files created before an error")
+	flag.BoolVar(&bResume, "resume", false, "resume the split with '-l', '-b' and '-C' from the journal next to the output files")
+	flag.BoolVar(&bSpool, "spool", false, "copy the input of unknown size into a temporary file for '-n'")
+	flag.StringVar(&strInputSize, "input-size", "", "assume the input of unknown size is SIZE bytes for '-n N'")
+	flag.StringVar(&strManifest, "manifest", "", "write a JSON manifest of the input and output files to FILE")
 	flag.BoolVar(&bVerbose, "verbose", false, "print a diagnostic just before each output file is opened")
+	flag.Var(&dryRun, "dry-run", "print the output files as a table, or JSON with '-dry-run=json', without writing them")
+	flag.BoolVar(&bProgress, "progress", false, "show the progress on stderr; it is also printed on SIGUSR1")
 }
 
 func main() {
@@ -38,6 +167,22 @@ func main() {
 		prefix   string
 	)
 
+	// discard log output if envvar DEBUG is not set
+	if os.Getenv("DEBUG") == "" {
+		log.SetOutput(io.Discard)
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "join":
+			runJoin(os.Args[2:])
+			return
+		case "verify":
+			runVerify(os.Args[2:])
+			return
+		}
+	}
+
 	flag.Parse()
 
 	switch flag.NArg() {
@@ -52,31 +197,30 @@ func main() {
 		prefix = flag.Args()[1]
 	}
 
-	// discard log output if envvar DEBUG is not set
-	if os.Getenv("DEBUG") == "" {
-		log.SetOutput(io.Discard)
-	}
-
-	g := gosplit.New(filePath, prefix)
-	g.SetNumericSuffix(bNumericSuffix)
-	g.SetElideEmptyFiles(bElideEmptyFiles)
-	if bVerbose {
-		g.SetVerboseWriter(os.Stdout)
-	}
-
 	switch {
 	case bHelp:
 		usageFormat := `Usage: %s [OPTION]... [FILE [PREFIX]]
+  or:  %[1]s join [OPTION]... [PREFIX [FILE]]
+  or:  %[1]s verify [OPTION]... -manifest FILE [PREFIX]
 Output pieces of FILE to PREFIXaa, PREFIXab, ...;
 default size is 1000 lines, and default PREFIX is 'x'.
 
 With no FILE, or when FILE is -, read standard input.
+See '%[1]s join -help' and '%[1]s verify -help' for joining and verifying the pieces.
 
 `
 		additionalNote := `
 The SIZE argument is an integer and optional unit (example: 10K is 10*1024).
 Units are K,M,G,T,P,E,Z,Y (powers of 1024) or KB,MB,... (powers of 1000).
 Binary prefixes can be used, too: KiB=K, MiB=M, and so on.
+
+CHUNKS may be:
+  N       split into N files based on size of input
+  K/N     output Kth of N to stdout
+  l/N     split into N files without splitting lines/records
+  l/K/N   output Kth of N to stdout without splitting lines/records
+  r/N     like 'l' but use round robin distribution
+  r/K/N   likewise but only output Kth of N to stdout
 `
 		fmt.Printf(usageFormat, os.Args[0])
 		flag.PrintDefaults()
@@ -85,33 +229,188 @@ Binary prefixes can be used, too: KiB=K, MiB=M, and so on.
 	case bVersion:
 		fmt.Println("inaz2/GoSplit 1.0.0")
 		os.Exit(0)
+	}
+
+	compression, err := gosplit.ParseCompression(strCompress)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		log.Fatalf("%+v", err)
+	}
+
+	opts := gosplit.Options{
+		Suffix:          suffix,
+		ElideEmptyFiles: bElideEmptyFiles,
+		Filter:          strFilter,
+		Compress:        compression,
+		CompressLevel:   nCompressLevel,
+		CountCompressed: bCountCompressed,
+		Decompress:      bDecompress,
+		Jobs:            nJobs,
+		KeepPartial:     bKeepPartial,
+		Resume:          bResume,
+		Spool:           bSpool,
+	}
+	if strInputSize != "" {
+		inputSize, err := gosplit.ParseSize(strInputSize)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			log.Fatalf("%+v", err)
+		}
+		opts.InputSize = inputSize
+	}
+	if strSeparator != "" {
+		separator, err := gosplit.ParseSeparator(strSeparator)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			log.Fatalf("%+v", err)
+		}
+		opts.Separator = separator
+	}
+	if bVerbose {
+		opts.Verbose = os.Stdout
+	}
+	progress := newProgressDisplay(bProgress)
+	opts.Progress = progress.update
+	printStatusOnSignal(progress)
+	discardArchive := func() {}
+	// the dry run creates neither the archive nor the manifest
+	switch {
+	case strArchive != "" && dryRun.format != "":
+		// the output files are planned without the disk free space check as the same as the archive
+		opts.Sink = gosplit.NewMemorySink()
+	case strArchive != "":
+		closeArchive, discard := openArchive(strArchive, &opts)
+		discardArchive = discard
+		defer func() {
+			if err := closeArchive(); err != nil {
+				discardArchive()
+				fmt.Fprintln(os.Stderr, err)
+				log.Fatalf("%+v", err)
+			}
+		}()
+	}
+
+	if strManifest != "" && dryRun.format == "" {
+		// the manifest file is created only after splitting successfully
+		wManifest := &lazyFile{filePath: strManifest}
+		opts.Manifest = wManifest
+		defer func() {
+			if err := wManifest.Close(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				log.Fatalf("%+v", err)
+			}
+		}()
+	}
+
+	g, err := gosplit.New(filePath, prefix, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		log.Fatalf("%+v", err)
+	}
+	interruptOnSignal(g)
+
+	switch {
 	case nLines != 0:
+		if dryRun.format != "" {
+			runPlan(g, gosplit.SplitLines, int64(nLines))
+			return
+		}
 		err := g.ByLines(nLines)
 		if err != nil {
+			discardArchive()
+			exitIfInterrupted(err)
 			fmt.Fprintln(os.Stderr, err)
 			log.Fatalf("%+v", err)
 		}
-	case nNumber != 0:
-		err := g.ByNumber(nNumber)
+	case strChunks != "":
+		mode, k, nNumber, err := gosplit.ParseChunks(strChunks)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			log.Fatalf("%+v", err)
 		}
+		if k != 0 && strManifest != "" {
+			err := fmt.Errorf("no manifest is written with CHUNKS %#v", strChunks)
+			fmt.Fprintln(os.Stderr, err)
+			log.Fatalf("%+v", err)
+		}
+		if k != 0 && dryRun.format != "" {
+			err := fmt.Errorf("no dry run with CHUNKS %#v", strChunks)
+			fmt.Fprintln(os.Stderr, err)
+			log.Fatalf("%+v", err)
+		}
+		if dryRun.format != "" {
+			splitMode := map[gosplit.ChunkMode]gosplit.SplitMode{
+				gosplit.ChunkBytes:      gosplit.SplitNumber,
+				gosplit.ChunkLines:      gosplit.SplitLineNumber,
+				gosplit.ChunkRoundRobin: gosplit.SplitRoundRobin,
+			}[mode]
+			runPlan(g, splitMode, int64(nNumber))
+			return
+		}
+		switch {
+		case mode == gosplit.ChunkLines && k == 0:
+			err = g.ByLineNumber(nNumber)
+		case mode == gosplit.ChunkLines:
+			err = g.ExtractByLineNumber(k, nNumber)
+		case mode == gosplit.ChunkRoundRobin && k == 0:
+			err = g.ByRoundRobin(nNumber)
+		case mode == gosplit.ChunkRoundRobin:
+			err = g.ExtractByRoundRobin(k, nNumber)
+		case k == 0:
+			err = g.ByNumber(nNumber)
+		default:
+			err = g.ExtractByNumber(k, nNumber)
+		}
+		if err != nil {
+			discardArchive()
+			exitIfInterrupted(err)
+			fmt.Fprintln(os.Stderr, err)
+			log.Fatalf("%+v", err)
+		}
 	case strSize != "":
-		nBytes, err := g.ParseSize(strSize)
+		nBytes, err := gosplit.ParseSize(strSize)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			log.Fatalf("%+v", err)
 		}
+		if dryRun.format != "" {
+			runPlan(g, gosplit.SplitBytes, nBytes)
+			return
+		}
 		err = g.ByBytes(nBytes)
+		if err != nil {
+			discardArchive()
+			exitIfInterrupted(err)
+			fmt.Fprintln(os.Stderr, err)
+			log.Fatalf("%+v", err)
+		}
+	case strLineBytes != "":
+		nBytes, err := gosplit.ParseSize(strLineBytes)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			log.Fatalf("%+v", err)
 		}
+		if dryRun.format != "" {
+			runPlan(g, gosplit.SplitLineBytes, nBytes)
+			return
+		}
+		err = g.ByLineBytes(nBytes)
+		if err != nil {
+			discardArchive()
+			exitIfInterrupted(err)
+			fmt.Fprintln(os.Stderr, err)
+			log.Fatalf("%+v", err)
+		}
 	default:
 		nLines = 1000
+		if dryRun.format != "" {
+			runPlan(g, gosplit.SplitLines, int64(nLines))
+			return
+		}
 		err := g.ByLines(nLines)
 		if err != nil {
+			discardArchive()
+			exitIfInterrupted(err)
 			fmt.Fprintln(os.Stderr, err)
 			log.Fatalf("%+v", err)
 		}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// plainProgressInterval is the interval of the progress lines when stderr is not a terminal.
+const plainProgressInterval = 5 * time.Second
+
+// progressDisplay shows the progress of the split on stderr, redrawing a line on a terminal
+// or printing a line periodically otherwise.
+type progressDisplay struct {
+	mu      sync.Mutex
+	w       io.Writer
+	bShow   bool
+	bTTY    bool
+	current gosplit.Progress
+	printed time.Time
+}
+
+// newProgressDisplay returns progressDisplay writing to stderr, which shows the progress only if bShow.
+func newProgressDisplay(bShow bool) *progressDisplay {
+	return &progressDisplay{w: os.Stderr, bShow: bShow, bTTY: isTerminal(os.Stderr), current: gosplit.Progress{TotalSize: -1, ChunkIndex: -1}}
+}
+
+// update implements the callback of gosplit.Options.Progress.
+func (d *progressDisplay) update(p gosplit.Progress) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.current = p
+	if !d.bShow {
+		return
+	}
+
+	switch {
+	case d.bTTY && p.Done:
+		fmt.Fprintf(d.w, "\r%s\033[K\n", formatProgress(p))
+	case d.bTTY:
+		fmt.Fprintf(d.w, "\r%s\033[K", formatProgress(p))
+	case p.Done || time.Since(d.printed) >= plainProgressInterval:
+		fmt.Fprintln(d.w, formatProgress(p))
+		d.printed = time.Now()
+	}
+}
+
+// printStatus prints the current progress on its own line, as the same as dd on SIGUSR1.
+func (d *progressDisplay) printStatus() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.bShow && d.bTTY && !d.current.Done {
+		// keep the redrawn line below the status line
+		fmt.Fprintf(d.w, "\r%s\033[K\n%[1]s", formatProgress(d.current))
+		return
+	}
+	fmt.Fprintln(d.w, formatProgress(d.current))
+}
+
+// printStatusOnSignal prints the progress of d on the status signals, i.e. SIGUSR1 if it is supported.
+func printStatusOnSignal(d *progressDisplay) {
+	if len(statusSignals) == 0 {
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, statusSignals...)
+	go func() {
+		for range ch {
+			d.printStatus()
+		}
+	}()
+}
+
+// formatProgress returns the line of p, e.g. "1.5 MiB / 6.0 MiB (25%), 2 files, 3.0 MiB/s, 0.5s elapsed".
+func formatProgress(p gosplit.Progress) string {
+	read := formatSize(p.BytesRead)
+	if p.TotalSize > 0 {
+		read = fmt.Sprintf("%s / %s (%d%%)", read, formatSize(p.TotalSize), p.BytesRead*100/p.TotalSize)
+	}
+	return fmt.Sprintf("%s, %d files, %s/s, %s elapsed",
+		read, p.ChunkIndex+1, formatSize(int64(p.BytesPerSecond)), p.Elapsed.Round(100*time.Millisecond))
+}
+
+// formatSize returns n bytes with the binary unit, e.g. "1.5 MiB".
+func formatSize(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	size := float64(n)
+	for _, unit := range []string{"KiB", "MiB", "GiB", "TiB", "PiB"} {
+		size /= 1024
+		if size < 1024 {
+			return fmt.Sprintf("%.1f %s", size, unit)
+		}
+	}
+	return fmt.Sprintf("%.1f EiB", size/1024)
+}
+
+// isTerminal returns true if f is a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
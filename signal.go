@@ -0,0 +1,44 @@
+package main
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+)
+
+// caughtSignal is the signal which has interrupted the split.
+var caughtSignal atomic.Value
+
+// interruptOnSignal interrupts the split of g at a safe point on SIGINT or SIGTERM.
+//
+// The signals are handled only once, so that the second one terminates the process immediately.
+func interruptOnSignal(g *gosplit.GoSplit) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-ch
+		signal.Stop(ch)
+		caughtSignal.Store(sig)
+		g.Interrupt()
+	}()
+}
+
+// exitIfInterrupted prints the report and exits with 128 + the signal number if err is ErrInterrupted,
+// i.e. 130 for SIGINT and 143 for SIGTERM.
+func exitIfInterrupted(err error) {
+	if !errors.Is(err, gosplit.ErrInterrupted) {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+	status := 130
+	if sig, ok := caughtSignal.Load().(syscall.Signal); ok {
+		status = 128 + int(sig)
+	}
+	os.Exit(status)
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// statusSignals are the signals to print the progress of the split.
+var statusSignals = []os.Signal{syscall.SIGUSR1}
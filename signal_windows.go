@@ -0,0 +1,10 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+)
+
+// statusSignals are the signals to print the progress of the split, which are not supported on Windows.
+var statusSignals []os.Signal
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"inaz2/GoSplit/gosplit"
+
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+// runVerify runs the verify subcommand with args, which checks the pieces of PREFIX
+// against the manifest or the source file.
+func runVerify(args []string) {
+	var (
+		bHelp       bool
+		bVerbose    bool
+		strManifest string
+		strSource   string
+		suffix      gosplit.Suffix
+		strCompress string
+		prefix      = "x"
+	)
+
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	fs.BoolVar(&bHelp, "help", false, "display this help and exit")
+	fs.StringVar(&strManifest, "manifest", "", "verify against the JSON manifest FILE written by the split")
+	fs.StringVar(&strSource, "source", "", "verify against the original FILE")
+	addSuffixFlags(fs, &suffix)
+	fs.StringVar(&strCompress, "compress", "", "decompress the pieces compressed with FORMAT by the split")
+	fs.BoolVar(&bVerbose, "verbose", false, "print a diagnostic just before each piece is read")
+	fs.Parse(args)
+
+	if bHelp {
+		usageFormat := `Usage: %s verify [OPTION]... -manifest FILE [PREFIX]
+  or:  %[1]s verify [OPTION]... -source FILE [PREFIX]
+Check that PREFIXaa, PREFIXab, ... reproduce the input of the split;
+default PREFIX is 'x'. With -source, give the same suffix options as the split.
+
+Report the pieces missing, truncated, modified or unexpected, and exit with non-zero status.
+
+`
+		fmt.Printf(usageFormat, os.Args[0])
+		fs.PrintDefaults()
+		os.Exit(0)
+	}
+
+	if fs.NArg() > 0 {
+		prefix = fs.Args()[0]
+	}
+
+	compression, err := gosplit.ParseCompression(strCompress)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		log.Fatalf("%+v", err)
+	}
+
+	opts := gosplit.Options{Suffix: suffix, Compress: compression}
+	if bVerbose {
+		opts.Verbose = os.Stderr
+	}
+
+	switch {
+	case strManifest != "" && strSource != "":
+		err = errors.New("either -manifest or -source must be given, not both")
+	case strManifest != "":
+		err = verifyManifest(strManifest, prefix, opts)
+	case strSource != "":
+		err = gosplit.VerifySource(strSource, prefix, opts)
+	default:
+		err = errors.New("either -manifest or -source must be given")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		log.Fatalf("%+v", err)
+	}
+}
+
+// verifyManifest checks the pieces of prefix against the manifest file of manifestPath.
+func verifyManifest(manifestPath string, prefix string, opts gosplit.Options) error {
+	f, err := os.Open(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to open: %w", err)
+	}
+	defer f.Close()
+
+	manifest, err := gosplit.ReadManifest(f)
+	if err != nil {
+		return err
+	}
+	return gosplit.Verify(manifest, prefix, opts)
+}